@@ -0,0 +1,8 @@
+data "fly_apps" "staging" {
+  org         = "my-org"
+  name_prefix = "staging-"
+}
+
+output "staging_hostnames" {
+  value = [for a in data.fly_apps.staging.apps : a.hostname if a.deployed]
+}
@@ -0,0 +1,8 @@
+data "fly_machine" "db" {
+  app  = "hellofromterraform"
+  name = "db-primary"
+}
+
+output "db_private_ip" {
+  value = data.fly_machine.db.privateip
+}
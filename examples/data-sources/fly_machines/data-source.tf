@@ -0,0 +1,11 @@
+data "fly_machines" "web" {
+  app   = "hellofromterraform"
+  state = "started"
+  metadata = {
+    role = "web"
+  }
+}
+
+output "web_private_ips" {
+  value = [for m in data.fly_machines.web.machines : m.private_ip]
+}
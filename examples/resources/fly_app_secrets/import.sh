@@ -0,0 +1 @@
+terraform import fly_app_secrets.exampleSecrets <app_name>
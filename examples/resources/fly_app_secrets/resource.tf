@@ -0,0 +1,6 @@
+resource "fly_app_secrets" "exampleSecrets" {
+  app = fly_app.exampleApp.name
+  secrets = {
+    DATABASE_URL = var.database_url
+  }
+}
@@ -0,0 +1,17 @@
+resource "fly_machine_exec" "migrate" {
+  app        = fly_machine.exampleMachine.app
+  machine_id = fly_machine.exampleMachine.id
+  command    = ["bin/rails", "db:migrate"]
+  timeout    = "10m"
+
+  triggers = {
+    image = fly_machine.exampleMachine.image
+  }
+
+  lifecycle {
+    postcondition {
+      condition     = self.exit_code == 0
+      error_message = "Migration failed: ${self.stderr}"
+    }
+  }
+}
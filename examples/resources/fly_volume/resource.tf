@@ -3,4 +3,8 @@ resource "fly_volume" "exampleApp" {
   app    = "hellofromterraform"
   size   = 10
   region = "ewr"
+
+  encrypted           = true
+  require_unique_zone = true
+  snapshot_retention  = 10
 }
@@ -183,6 +183,106 @@ func (v *AllocateIpAddressResponse) GetAllocateIpAddress() AllocateIpAddressAllo
 	return v.AllocateIpAddress
 }
 
+// AppSecretsQueryApp includes the requested fields of the GraphQL type App.
+type AppSecretsQueryApp struct {
+	Secrets []AppSecretsQueryAppSecretsSecret `json:"secrets"`
+}
+
+// GetSecrets returns AppSecretsQueryApp.Secrets, and is useful for accessing the field via an interface.
+func (v *AppSecretsQueryApp) GetSecrets() []AppSecretsQueryAppSecretsSecret { return v.Secrets }
+
+// AppSecretsQueryAppSecretsSecret includes the requested fields of the GraphQL type Secret.
+type AppSecretsQueryAppSecretsSecret struct {
+	Name   string `json:"name"`
+	Digest string `json:"digest"`
+}
+
+// GetName returns AppSecretsQueryAppSecretsSecret.Name, and is useful for accessing the field via an interface.
+func (v *AppSecretsQueryAppSecretsSecret) GetName() string { return v.Name }
+
+// GetDigest returns AppSecretsQueryAppSecretsSecret.Digest, and is useful for accessing the field via an interface.
+func (v *AppSecretsQueryAppSecretsSecret) GetDigest() string { return v.Digest }
+
+// AppSecretsQueryResponse is returned by AppSecretsQuery on success.
+type AppSecretsQueryResponse struct {
+	App AppSecretsQueryApp `json:"app"`
+}
+
+// GetApp returns AppSecretsQueryResponse.App, and is useful for accessing the field via an interface.
+func (v *AppSecretsQueryResponse) GetApp() AppSecretsQueryApp { return v.App }
+
+// AppsQueryAppsAppConnection includes the requested fields of the GraphQL type AppConnection.
+type AppsQueryAppsAppConnection struct {
+	Nodes    []AppsQueryAppsAppConnectionNodesApp `json:"nodes"`
+	PageInfo AppsQueryAppsAppConnectionPageInfo   `json:"pageInfo"`
+}
+
+// GetNodes returns AppsQueryAppsAppConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnection) GetNodes() []AppsQueryAppsAppConnectionNodesApp { return v.Nodes }
+
+// GetPageInfo returns AppsQueryAppsAppConnection.PageInfo, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnection) GetPageInfo() AppsQueryAppsAppConnectionPageInfo {
+	return v.PageInfo
+}
+
+// AppsQueryAppsAppConnectionNodesApp includes the requested fields of the GraphQL type App.
+type AppsQueryAppsAppConnectionNodesApp struct {
+	Id           string                                         `json:"id"`
+	Name         string                                         `json:"name"`
+	Status       string                                         `json:"status"`
+	Deployed     bool                                           `json:"deployed"`
+	Hostname     string                                         `json:"hostname"`
+	Organization AppsQueryAppsAppConnectionNodesAppOrganization `json:"organization"`
+}
+
+// GetId returns AppsQueryAppsAppConnectionNodesApp.Id, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnectionNodesApp) GetId() string { return v.Id }
+
+// GetName returns AppsQueryAppsAppConnectionNodesApp.Name, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnectionNodesApp) GetName() string { return v.Name }
+
+// GetStatus returns AppsQueryAppsAppConnectionNodesApp.Status, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnectionNodesApp) GetStatus() string { return v.Status }
+
+// GetDeployed returns AppsQueryAppsAppConnectionNodesApp.Deployed, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnectionNodesApp) GetDeployed() bool { return v.Deployed }
+
+// GetHostname returns AppsQueryAppsAppConnectionNodesApp.Hostname, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnectionNodesApp) GetHostname() string { return v.Hostname }
+
+// GetOrganization returns AppsQueryAppsAppConnectionNodesApp.Organization, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnectionNodesApp) GetOrganization() AppsQueryAppsAppConnectionNodesAppOrganization {
+	return v.Organization
+}
+
+// AppsQueryAppsAppConnectionNodesAppOrganization includes the requested fields of the GraphQL type Organization.
+type AppsQueryAppsAppConnectionNodesAppOrganization struct {
+	Slug string `json:"slug"`
+}
+
+// GetSlug returns AppsQueryAppsAppConnectionNodesAppOrganization.Slug, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnectionNodesAppOrganization) GetSlug() string { return v.Slug }
+
+// AppsQueryAppsAppConnectionPageInfo includes the requested fields of the GraphQL type PageInfo.
+type AppsQueryAppsAppConnectionPageInfo struct {
+	EndCursor   string `json:"endCursor"`
+	HasNextPage bool   `json:"hasNextPage"`
+}
+
+// GetEndCursor returns AppsQueryAppsAppConnectionPageInfo.EndCursor, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnectionPageInfo) GetEndCursor() string { return v.EndCursor }
+
+// GetHasNextPage returns AppsQueryAppsAppConnectionPageInfo.HasNextPage, and is useful for accessing the field via an interface.
+func (v *AppsQueryAppsAppConnectionPageInfo) GetHasNextPage() bool { return v.HasNextPage }
+
+// AppsQueryResponse is returned by AppsQuery on success.
+type AppsQueryResponse struct {
+	Apps AppsQueryAppsAppConnection `json:"apps"`
+}
+
+// GetApps returns AppsQueryResponse.Apps, and is useful for accessing the field via an interface.
+func (v *AppsQueryResponse) GetApps() AppsQueryAppsAppConnection { return v.Apps }
+
 type AutoscaleRegionConfigInput struct {
 	Code     string `json:"code"`
 	Weight   int    `json:"weight"`
@@ -319,6 +419,7 @@ type CreateVolumeCreateVolumeCreateVolumePayloadVolume struct {
 	Id         string `json:"id"`
 	InternalId string `json:"internalId"`
 	SizeGb     int    `json:"sizeGb"`
+	Encrypted  bool   `json:"encrypted"`
 }
 
 // GetName returns CreateVolumeCreateVolumeCreateVolumePayloadVolume.Name, and is useful for accessing the field via an interface.
@@ -338,6 +439,9 @@ func (v *CreateVolumeCreateVolumeCreateVolumePayloadVolume) GetInternalId() stri
 // GetSizeGb returns CreateVolumeCreateVolumeCreateVolumePayloadVolume.SizeGb, and is useful for accessing the field via an interface.
 func (v *CreateVolumeCreateVolumeCreateVolumePayloadVolume) GetSizeGb() int { return v.SizeGb }
 
+// GetEncrypted returns CreateVolumeCreateVolumeCreateVolumePayloadVolume.Encrypted, and is useful for accessing the field via an interface.
+func (v *CreateVolumeCreateVolumeCreateVolumePayloadVolume) GetEncrypted() bool { return v.Encrypted }
+
 // CreateVolumeResponse is returned by CreateVolume on success.
 type CreateVolumeResponse struct {
 	CreateVolume CreateVolumeCreateVolumeCreateVolumePayload `json:"createVolume"`
@@ -498,20 +602,24 @@ func (v *GetCertificateResponse) GetApp() GetCertificateApp { return v.App }
 
 // GetFullAppApp includes the requested fields of the GraphQL type App.
 type GetFullAppApp struct {
-	Name           string                                        `json:"name"`
-	Network        string                                        `json:"network"`
-	Organization   GetFullAppAppOrganization                     `json:"organization"`
-	Autoscaling    GetFullAppAppAutoscalingAutoscalingConfig     `json:"autoscaling"`
-	AppUrl         string                                        `json:"appUrl"`
-	Hostname       string                                        `json:"hostname"`
-	Id             string                                        `json:"id"`
-	Status         string                                        `json:"status"`
-	Deployed       bool                                          `json:"deployed"`
-	CurrentRelease GetFullAppAppCurrentRelease                   `json:"currentRelease"`
-	Config         GetFullAppAppConfig                           `json:"config"`
-	HealthChecks   GetFullAppAppHealthChecksCheckStateConnection `json:"healthChecks"`
-	IpAddresses    GetFullAppAppIpAddressesIPAddressConnection   `json:"ipAddresses"`
-	Role           GetFullAppAppRole                             `json:"-"`
+	Name             string                                        `json:"name"`
+	Network          string                                        `json:"network"`
+	Organization     GetFullAppAppOrganization                     `json:"organization"`
+	Autoscaling      GetFullAppAppAutoscalingAutoscalingConfig     `json:"autoscaling"`
+	AppUrl           string                                        `json:"appUrl"`
+	Hostname         string                                        `json:"hostname"`
+	Id               string                                        `json:"id"`
+	Status           string                                        `json:"status"`
+	Deployed         bool                                          `json:"deployed"`
+	CurrentRelease   GetFullAppAppCurrentRelease                   `json:"currentRelease"`
+	Releases         GetFullAppAppReleasesReleaseConnection        `json:"releases"`
+	DeploymentStatus GetFullAppAppDeploymentStatus                 `json:"deploymentStatus"`
+	Regions          []GetFullAppAppRegionsRegion                  `json:"regions"`
+	Machines         GetFullAppAppMachinesMachineConnection        `json:"machines"`
+	Config           GetFullAppAppConfig                           `json:"config"`
+	HealthChecks     GetFullAppAppHealthChecksCheckStateConnection `json:"healthChecks"`
+	IpAddresses      GetFullAppAppIpAddressesIPAddressConnection   `json:"ipAddresses"`
+	Role             GetFullAppAppRole                             `json:"-"`
 }
 
 // GetName returns GetFullAppApp.Name, and is useful for accessing the field via an interface.
@@ -546,6 +654,20 @@ func (v *GetFullAppApp) GetDeployed() bool { return v.Deployed }
 // GetCurrentRelease returns GetFullAppApp.CurrentRelease, and is useful for accessing the field via an interface.
 func (v *GetFullAppApp) GetCurrentRelease() GetFullAppAppCurrentRelease { return v.CurrentRelease }
 
+// GetReleases returns GetFullAppApp.Releases, and is useful for accessing the field via an interface.
+func (v *GetFullAppApp) GetReleases() GetFullAppAppReleasesReleaseConnection { return v.Releases }
+
+// GetDeploymentStatus returns GetFullAppApp.DeploymentStatus, and is useful for accessing the field via an interface.
+func (v *GetFullAppApp) GetDeploymentStatus() GetFullAppAppDeploymentStatus {
+	return v.DeploymentStatus
+}
+
+// GetRegions returns GetFullAppApp.Regions, and is useful for accessing the field via an interface.
+func (v *GetFullAppApp) GetRegions() []GetFullAppAppRegionsRegion { return v.Regions }
+
+// GetMachines returns GetFullAppApp.Machines, and is useful for accessing the field via an interface.
+func (v *GetFullAppApp) GetMachines() GetFullAppAppMachinesMachineConnection { return v.Machines }
+
 // GetConfig returns GetFullAppApp.Config, and is useful for accessing the field via an interface.
 func (v *GetFullAppApp) GetConfig() GetFullAppAppConfig { return v.Config }
 
@@ -616,6 +738,14 @@ type __premarshalGetFullAppApp struct {
 
 	CurrentRelease GetFullAppAppCurrentRelease `json:"currentRelease"`
 
+	Releases GetFullAppAppReleasesReleaseConnection `json:"releases"`
+
+	DeploymentStatus GetFullAppAppDeploymentStatus `json:"deploymentStatus"`
+
+	Regions []GetFullAppAppRegionsRegion `json:"regions"`
+
+	Machines GetFullAppAppMachinesMachineConnection `json:"machines"`
+
 	Config GetFullAppAppConfig `json:"config"`
 
 	HealthChecks GetFullAppAppHealthChecksCheckStateConnection `json:"healthChecks"`
@@ -646,6 +776,10 @@ func (v *GetFullAppApp) __premarshalJSON() (*__premarshalGetFullAppApp, error) {
 	retval.Status = v.Status
 	retval.Deployed = v.Deployed
 	retval.CurrentRelease = v.CurrentRelease
+	retval.Releases = v.Releases
+	retval.DeploymentStatus = v.DeploymentStatus
+	retval.Regions = v.Regions
+	retval.Machines = v.Machines
 	retval.Config = v.Config
 	retval.HealthChecks = v.HealthChecks
 	retval.IpAddresses = v.IpAddresses
@@ -706,6 +840,42 @@ type GetFullAppAppCurrentRelease struct {
 // GetId returns GetFullAppAppCurrentRelease.Id, and is useful for accessing the field via an interface.
 func (v *GetFullAppAppCurrentRelease) GetId() string { return v.Id }
 
+// GetFullAppAppDeploymentStatus includes the requested fields of the GraphQL type DeploymentStatus.
+type GetFullAppAppDeploymentStatus struct {
+	Id             string `json:"id"`
+	Status         string `json:"status"`
+	Description    string `json:"description"`
+	Version        int    `json:"version"`
+	DesiredCount   int    `json:"desiredCount"`
+	PlacedCount    int    `json:"placedCount"`
+	HealthyCount   int    `json:"healthyCount"`
+	UnhealthyCount int    `json:"unhealthyCount"`
+}
+
+// GetId returns GetFullAppAppDeploymentStatus.Id, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppDeploymentStatus) GetId() string { return v.Id }
+
+// GetStatus returns GetFullAppAppDeploymentStatus.Status, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppDeploymentStatus) GetStatus() string { return v.Status }
+
+// GetDescription returns GetFullAppAppDeploymentStatus.Description, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppDeploymentStatus) GetDescription() string { return v.Description }
+
+// GetVersion returns GetFullAppAppDeploymentStatus.Version, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppDeploymentStatus) GetVersion() int { return v.Version }
+
+// GetDesiredCount returns GetFullAppAppDeploymentStatus.DesiredCount, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppDeploymentStatus) GetDesiredCount() int { return v.DesiredCount }
+
+// GetPlacedCount returns GetFullAppAppDeploymentStatus.PlacedCount, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppDeploymentStatus) GetPlacedCount() int { return v.PlacedCount }
+
+// GetHealthyCount returns GetFullAppAppDeploymentStatus.HealthyCount, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppDeploymentStatus) GetHealthyCount() int { return v.HealthyCount }
+
+// GetUnhealthyCount returns GetFullAppAppDeploymentStatus.UnhealthyCount, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppDeploymentStatus) GetUnhealthyCount() int { return v.UnhealthyCount }
+
 // GetFullAppAppHealthChecksCheckStateConnection includes the requested fields of the GraphQL type CheckStateConnection.
 type GetFullAppAppHealthChecksCheckStateConnection struct {
 	Nodes []GetFullAppAppHealthChecksCheckStateConnectionNodesCheckState `json:"nodes"`
@@ -756,6 +926,14 @@ func (v *GetFullAppAppIpAddressesIPAddressConnectionNodesIPAddress) GetAddress()
 // GetId returns GetFullAppAppIpAddressesIPAddressConnectionNodesIPAddress.Id, and is useful for accessing the field via an interface.
 func (v *GetFullAppAppIpAddressesIPAddressConnectionNodesIPAddress) GetId() string { return v.Id }
 
+// GetFullAppAppMachinesMachineConnection includes the requested fields of the GraphQL type MachineConnection.
+type GetFullAppAppMachinesMachineConnection struct {
+	TotalCount int `json:"totalCount"`
+}
+
+// GetTotalCount returns GetFullAppAppMachinesMachineConnection.TotalCount, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppMachinesMachineConnection) GetTotalCount() int { return v.TotalCount }
+
 // GetFullAppAppOrganization includes the requested fields of the GraphQL type Organization.
 type GetFullAppAppOrganization struct {
 	Id   string `json:"id"`
@@ -768,6 +946,46 @@ func (v *GetFullAppAppOrganization) GetId() string { return v.Id }
 // GetSlug returns GetFullAppAppOrganization.Slug, and is useful for accessing the field via an interface.
 func (v *GetFullAppAppOrganization) GetSlug() string { return v.Slug }
 
+// GetFullAppAppRegionsRegion includes the requested fields of the GraphQL type Region.
+type GetFullAppAppRegionsRegion struct {
+	Code string `json:"code"`
+}
+
+// GetCode returns GetFullAppAppRegionsRegion.Code, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppRegionsRegion) GetCode() string { return v.Code }
+
+// GetFullAppAppReleasesReleaseConnection includes the requested fields of the GraphQL type ReleaseConnection.
+type GetFullAppAppReleasesReleaseConnection struct {
+	Nodes []GetFullAppAppReleasesReleaseConnectionNodesRelease `json:"nodes"`
+}
+
+// GetNodes returns GetFullAppAppReleasesReleaseConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppReleasesReleaseConnection) GetNodes() []GetFullAppAppReleasesReleaseConnectionNodesRelease {
+	return v.Nodes
+}
+
+// GetFullAppAppReleasesReleaseConnectionNodesRelease includes the requested fields of the GraphQL type Release.
+type GetFullAppAppReleasesReleaseConnectionNodesRelease struct {
+	Version   int    `json:"version"`
+	Status    string `json:"status"`
+	ImageRef  string `json:"imageRef"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// GetVersion returns GetFullAppAppReleasesReleaseConnectionNodesRelease.Version, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppReleasesReleaseConnectionNodesRelease) GetVersion() int { return v.Version }
+
+// GetStatus returns GetFullAppAppReleasesReleaseConnectionNodesRelease.Status, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppReleasesReleaseConnectionNodesRelease) GetStatus() string { return v.Status }
+
+// GetImageRef returns GetFullAppAppReleasesReleaseConnectionNodesRelease.ImageRef, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppReleasesReleaseConnectionNodesRelease) GetImageRef() string { return v.ImageRef }
+
+// GetCreatedAt returns GetFullAppAppReleasesReleaseConnectionNodesRelease.CreatedAt, and is useful for accessing the field via an interface.
+func (v *GetFullAppAppReleasesReleaseConnectionNodesRelease) GetCreatedAt() string {
+	return v.CreatedAt
+}
+
 // GetFullAppAppRole includes the requested fields of the GraphQL interface AppRole.
 //
 // GetFullAppAppRole is implemented by the following types:
@@ -967,6 +1185,58 @@ type IpAddressQueryResponse struct {
 // GetApp returns IpAddressQueryResponse.App, and is useful for accessing the field via an interface.
 func (v *IpAddressQueryResponse) GetApp() IpAddressQueryApp { return v.App }
 
+// MoveAppMutationMoveAppMoveAppPayload includes the requested fields of the GraphQL type MoveAppPayload.
+type MoveAppMutationMoveAppMoveAppPayload struct {
+	App MoveAppMutationMoveAppMoveAppPayloadApp `json:"app"`
+}
+
+// GetApp returns MoveAppMutationMoveAppMoveAppPayload.App, and is useful for accessing the field via an interface.
+func (v *MoveAppMutationMoveAppMoveAppPayload) GetApp() MoveAppMutationMoveAppMoveAppPayloadApp {
+	return v.App
+}
+
+// MoveAppMutationMoveAppMoveAppPayloadApp includes the requested fields of the GraphQL type App.
+type MoveAppMutationMoveAppMoveAppPayloadApp struct {
+	Id           string                                              `json:"id"`
+	Name         string                                              `json:"name"`
+	Organization MoveAppMutationMoveAppMoveAppPayloadAppOrganization `json:"organization"`
+	AppUrl       string                                              `json:"appUrl"`
+}
+
+// GetId returns MoveAppMutationMoveAppMoveAppPayloadApp.Id, and is useful for accessing the field via an interface.
+func (v *MoveAppMutationMoveAppMoveAppPayloadApp) GetId() string { return v.Id }
+
+// GetName returns MoveAppMutationMoveAppMoveAppPayloadApp.Name, and is useful for accessing the field via an interface.
+func (v *MoveAppMutationMoveAppMoveAppPayloadApp) GetName() string { return v.Name }
+
+// GetOrganization returns MoveAppMutationMoveAppMoveAppPayloadApp.Organization, and is useful for accessing the field via an interface.
+func (v *MoveAppMutationMoveAppMoveAppPayloadApp) GetOrganization() MoveAppMutationMoveAppMoveAppPayloadAppOrganization {
+	return v.Organization
+}
+
+// GetAppUrl returns MoveAppMutationMoveAppMoveAppPayloadApp.AppUrl, and is useful for accessing the field via an interface.
+func (v *MoveAppMutationMoveAppMoveAppPayloadApp) GetAppUrl() string { return v.AppUrl }
+
+// MoveAppMutationMoveAppMoveAppPayloadAppOrganization includes the requested fields of the GraphQL type Organization.
+type MoveAppMutationMoveAppMoveAppPayloadAppOrganization struct {
+	Id   string `json:"id"`
+	Slug string `json:"slug"`
+}
+
+// GetId returns MoveAppMutationMoveAppMoveAppPayloadAppOrganization.Id, and is useful for accessing the field via an interface.
+func (v *MoveAppMutationMoveAppMoveAppPayloadAppOrganization) GetId() string { return v.Id }
+
+// GetSlug returns MoveAppMutationMoveAppMoveAppPayloadAppOrganization.Slug, and is useful for accessing the field via an interface.
+func (v *MoveAppMutationMoveAppMoveAppPayloadAppOrganization) GetSlug() string { return v.Slug }
+
+// MoveAppMutationResponse is returned by MoveAppMutation on success.
+type MoveAppMutationResponse struct {
+	MoveApp MoveAppMutationMoveAppMoveAppPayload `json:"moveApp"`
+}
+
+// GetMoveApp returns MoveAppMutationResponse.MoveApp, and is useful for accessing the field via an interface.
+func (v *MoveAppMutationResponse) GetMoveApp() MoveAppMutationMoveAppMoveAppPayload { return v.MoveApp }
+
 // OrganizationOrganization includes the requested fields of the GraphQL type Organization.
 type OrganizationOrganization struct {
 	Id string `json:"id"`
@@ -1152,6 +1422,49 @@ type SetSecretsSetSecretsSetSecretsPayloadRelease struct {
 // GetId returns SetSecretsSetSecretsSetSecretsPayloadRelease.Id, and is useful for accessing the field via an interface.
 func (v *SetSecretsSetSecretsSetSecretsPayloadRelease) GetId() string { return v.Id }
 
+type UnsetSecretsInput struct {
+	ClientMutationId string   `json:"clientMutationId"`
+	AppId            string   `json:"appId"`
+	Keys             []string `json:"keys"`
+}
+
+// GetClientMutationId returns UnsetSecretsInput.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *UnsetSecretsInput) GetClientMutationId() string { return v.ClientMutationId }
+
+// GetAppId returns UnsetSecretsInput.AppId, and is useful for accessing the field via an interface.
+func (v *UnsetSecretsInput) GetAppId() string { return v.AppId }
+
+// GetKeys returns UnsetSecretsInput.Keys, and is useful for accessing the field via an interface.
+func (v *UnsetSecretsInput) GetKeys() []string { return v.Keys }
+
+// UnsetSecretsResponse is returned by UnsetSecrets on success.
+type UnsetSecretsResponse struct {
+	UnsetSecrets UnsetSecretsUnsetSecretsUnsetSecretsPayload `json:"unsetSecrets"`
+}
+
+// GetUnsetSecrets returns UnsetSecretsResponse.UnsetSecrets, and is useful for accessing the field via an interface.
+func (v *UnsetSecretsResponse) GetUnsetSecrets() UnsetSecretsUnsetSecretsUnsetSecretsPayload {
+	return v.UnsetSecrets
+}
+
+// UnsetSecretsUnsetSecretsUnsetSecretsPayload includes the requested fields of the GraphQL type UnsetSecretsPayload.
+type UnsetSecretsUnsetSecretsUnsetSecretsPayload struct {
+	Release UnsetSecretsUnsetSecretsUnsetSecretsPayloadRelease `json:"release"`
+}
+
+// GetRelease returns UnsetSecretsUnsetSecretsUnsetSecretsPayload.Release, and is useful for accessing the field via an interface.
+func (v *UnsetSecretsUnsetSecretsUnsetSecretsPayload) GetRelease() UnsetSecretsUnsetSecretsUnsetSecretsPayloadRelease {
+	return v.Release
+}
+
+// UnsetSecretsUnsetSecretsUnsetSecretsPayloadRelease includes the requested fields of the GraphQL type Release.
+type UnsetSecretsUnsetSecretsUnsetSecretsPayloadRelease struct {
+	Id string `json:"id"`
+}
+
+// GetId returns UnsetSecretsUnsetSecretsUnsetSecretsPayloadRelease.Id, and is useful for accessing the field via an interface.
+func (v *UnsetSecretsUnsetSecretsUnsetSecretsPayloadRelease) GetId() string { return v.Id }
+
 // UpdateAutoScaleConfigMutationResponse is returned by UpdateAutoScaleConfigMutation on success.
 type UpdateAutoScaleConfigMutationResponse struct {
 	UpdateAutoscaleConfig UpdateAutoScaleConfigMutationUpdateAutoscaleConfigUpdateAutoscaleConfigPayload `json:"updateAutoscaleConfig"`
@@ -1223,6 +1536,8 @@ type VolumeQueryAppVolume struct {
 	Id         string `json:"id"`
 	InternalId string `json:"internalId"`
 	SizeGb     int    `json:"sizeGb"`
+	State      string `json:"state"`
+	Encrypted  bool   `json:"encrypted"`
 }
 
 // GetName returns VolumeQueryAppVolume.Name, and is useful for accessing the field via an interface.
@@ -1240,6 +1555,12 @@ func (v *VolumeQueryAppVolume) GetInternalId() string { return v.InternalId }
 // GetSizeGb returns VolumeQueryAppVolume.SizeGb, and is useful for accessing the field via an interface.
 func (v *VolumeQueryAppVolume) GetSizeGb() int { return v.SizeGb }
 
+// GetState returns VolumeQueryAppVolume.State, and is useful for accessing the field via an interface.
+func (v *VolumeQueryAppVolume) GetState() string { return v.State }
+
+// GetEncrypted returns VolumeQueryAppVolume.Encrypted, and is useful for accessing the field via an interface.
+func (v *VolumeQueryAppVolume) GetEncrypted() bool { return v.Encrypted }
+
 // VolumeQueryResponse is returned by VolumeQuery on success.
 type VolumeQueryResponse struct {
 	App VolumeQueryApp `json:"app"`
@@ -1284,6 +1605,22 @@ func (v *__AllocateIpAddressInput) GetRegion() string { return v.Region }
 // GetAddrType returns __AllocateIpAddressInput.AddrType, and is useful for accessing the field via an interface.
 func (v *__AllocateIpAddressInput) GetAddrType() IPAddressType { return v.AddrType }
 
+// __AppSecretsQueryInput is used internally by genqlient
+type __AppSecretsQueryInput struct {
+	App string `json:"app"`
+}
+
+// GetApp returns __AppSecretsQueryInput.App, and is useful for accessing the field via an interface.
+func (v *__AppSecretsQueryInput) GetApp() string { return v.App }
+
+// __AppsQueryInput is used internally by genqlient
+type __AppsQueryInput struct {
+	After string `json:"after,omitempty"`
+}
+
+// GetAfter returns __AppsQueryInput.After, and is useful for accessing the field via an interface.
+func (v *__AppsQueryInput) GetAfter() string { return v.After }
+
 // __CreateAppMutationInput is used internally by genqlient
 type __CreateAppMutationInput struct {
 	Name           string `json:"name"`
@@ -1334,10 +1671,12 @@ func (v *__CreatePostgresClusterInput) GetImageref() string { return v.Imageref
 
 // __CreateVolumeInput is used internally by genqlient
 type __CreateVolumeInput struct {
-	App    string `json:"app"`
-	Name   string `json:"name"`
-	Region string `json:"region"`
-	SizeGb int    `json:"sizeGb"`
+	App               string `json:"app"`
+	Name              string `json:"name"`
+	Region            string `json:"region"`
+	SizeGb            int    `json:"sizeGb"`
+	Encrypted         *bool  `json:"encrypted"`
+	RequireUniqueZone *bool  `json:"requireUniqueZone"`
 }
 
 // GetApp returns __CreateVolumeInput.App, and is useful for accessing the field via an interface.
@@ -1352,6 +1691,12 @@ func (v *__CreateVolumeInput) GetRegion() string { return v.Region }
 // GetSizeGb returns __CreateVolumeInput.SizeGb, and is useful for accessing the field via an interface.
 func (v *__CreateVolumeInput) GetSizeGb() int { return v.SizeGb }
 
+// GetEncrypted returns __CreateVolumeInput.Encrypted, and is useful for accessing the field via an interface.
+func (v *__CreateVolumeInput) GetEncrypted() *bool { return v.Encrypted }
+
+// GetRequireUniqueZone returns __CreateVolumeInput.RequireUniqueZone, and is useful for accessing the field via an interface.
+func (v *__CreateVolumeInput) GetRequireUniqueZone() *bool { return v.RequireUniqueZone }
+
 // __DeleteAppMutationInput is used internally by genqlient
 type __DeleteAppMutationInput struct {
 	Name string `json:"name"`
@@ -1412,6 +1757,18 @@ func (v *__IpAddressQueryInput) GetApp() string { return v.App }
 // GetAddr returns __IpAddressQueryInput.Addr, and is useful for accessing the field via an interface.
 func (v *__IpAddressQueryInput) GetAddr() string { return v.Addr }
 
+// __MoveAppMutationInput is used internally by genqlient
+type __MoveAppMutationInput struct {
+	AppId          string `json:"appId"`
+	OrganizationId string `json:"organizationId"`
+}
+
+// GetAppId returns __MoveAppMutationInput.AppId, and is useful for accessing the field via an interface.
+func (v *__MoveAppMutationInput) GetAppId() string { return v.AppId }
+
+// GetOrganizationId returns __MoveAppMutationInput.OrganizationId, and is useful for accessing the field via an interface.
+func (v *__MoveAppMutationInput) GetOrganizationId() string { return v.OrganizationId }
+
 // __OrganizationInput is used internally by genqlient
 type __OrganizationInput struct {
 	Slug string `json:"slug"`
@@ -1444,6 +1801,14 @@ type __SetSecretsInput struct {
 // GetInput returns __SetSecretsInput.Input, and is useful for accessing the field via an interface.
 func (v *__SetSecretsInput) GetInput() SetSecretsInput { return v.Input }
 
+// __UnsetSecretsInput is used internally by genqlient
+type __UnsetSecretsInput struct {
+	Input UnsetSecretsInput `json:"input"`
+}
+
+// GetInput returns __UnsetSecretsInput.Input, and is useful for accessing the field via an interface.
+func (v *__UnsetSecretsInput) GetInput() UnsetSecretsInput { return v.Input }
+
 // __UpdateAutoScaleConfigMutationInput is used internally by genqlient
 type __UpdateAutoScaleConfigMutationInput struct {
 	Id           string                       `json:"id"`
@@ -1591,6 +1956,86 @@ mutation AllocateIpAddress ($app: ID!, $region: String, $addrType: IPAddressType
 	return &data, err
 }
 
+func AppSecretsQuery(
+	ctx context.Context,
+	client graphql.Client,
+	app string,
+) (*AppSecretsQueryResponse, error) {
+	req := &graphql.Request{
+		OpName: "AppSecretsQuery",
+		Query: `
+query AppSecretsQuery ($app: String) {
+	app(name: $app) {
+		secrets {
+			name
+			digest
+		}
+	}
+}
+`,
+		Variables: &__AppSecretsQueryInput{
+			App: app,
+		},
+	}
+	var err error
+
+	var data AppSecretsQueryResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+func AppsQuery(
+	ctx context.Context,
+	client graphql.Client,
+	after string,
+) (*AppsQueryResponse, error) {
+	req := &graphql.Request{
+		OpName: "AppsQuery",
+		Query: `
+query AppsQuery ($after: String) {
+	apps(first: 100, after: $after) {
+		nodes {
+			id
+			name
+			status
+			deployed
+			hostname
+			organization {
+				slug
+			}
+		}
+		pageInfo {
+			endCursor
+			hasNextPage
+		}
+	}
+}
+`,
+		Variables: &__AppsQueryInput{
+			After: after,
+		},
+	}
+	var err error
+
+	var data AppsQueryResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func CreateAppMutation(
 	ctx context.Context,
 	client graphql.Client,
@@ -1691,12 +2136,14 @@ func CreateVolume(
 	name string,
 	region string,
 	sizeGb int,
+	encrypted *bool,
+	requireUniqueZone *bool,
 ) (*CreateVolumeResponse, error) {
 	req := &graphql.Request{
 		OpName: "CreateVolume",
 		Query: `
-mutation CreateVolume ($app: ID!, $name: String!, $region: String!, $sizeGb: Int!) {
-	createVolume(input: {appId:$app,name:$name,region:$region,sizeGb:$sizeGb}) {
+mutation CreateVolume ($app: ID!, $name: String!, $region: String!, $sizeGb: Int!, $encrypted: Boolean, $requireUniqueZone: Boolean) {
+	createVolume(input: {appId:$app,name:$name,region:$region,sizeGb:$sizeGb,encrypted:$encrypted,requireUniqueZone:$requireUniqueZone}) {
 		volume {
 			name
 			region
@@ -1704,15 +2151,18 @@ mutation CreateVolume ($app: ID!, $name: String!, $region: String!, $sizeGb: Int
 			internalId
 			region
 			sizeGb
+			encrypted
 		}
 	}
 }
 `,
 		Variables: &__CreateVolumeInput{
-			App:    app,
-			Name:   name,
-			Region: region,
-			SizeGb: sizeGb,
+			App:               app,
+			Name:              name,
+			Region:            region,
+			SizeGb:            sizeGb,
+			Encrypted:         encrypted,
+			RequireUniqueZone: requireUniqueZone,
 		},
 	}
 	var err error
@@ -1906,6 +2356,30 @@ query GetFullApp ($name: String) {
 		currentRelease {
 			id
 		}
+		releases(first: 10) {
+			nodes {
+				version
+				status
+				imageRef
+				createdAt
+			}
+		}
+		deploymentStatus {
+			id
+			status
+			description
+			version
+			desiredCount
+			placedCount
+			healthyCount
+			unhealthyCount
+		}
+		regions {
+			code
+		}
+		machines {
+			totalCount
+		}
 		config {
 			definition
 		}
@@ -1985,6 +2459,48 @@ query IpAddressQuery ($app: String, $addr: String!) {
 	return &data, err
 }
 
+func MoveAppMutation(
+	ctx context.Context,
+	client graphql.Client,
+	appId string,
+	organizationId string,
+) (*MoveAppMutationResponse, error) {
+	req := &graphql.Request{
+		OpName: "MoveAppMutation",
+		Query: `
+mutation MoveAppMutation ($appId: ID!, $organizationId: ID!) {
+	moveApp(input: {appId:$appId,organizationId:$organizationId}) {
+		app {
+			id
+			name
+			organization {
+				id
+				slug
+			}
+			appUrl
+		}
+	}
+}
+`,
+		Variables: &__MoveAppMutationInput{
+			AppId:          appId,
+			OrganizationId: organizationId,
+		},
+	}
+	var err error
+
+	var data MoveAppMutationResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func Organization(
 	ctx context.Context,
 	client graphql.Client,
@@ -2150,6 +2666,40 @@ mutation SetSecrets ($input: SetSecretsInput!) {
 	return &data, err
 }
 
+func UnsetSecrets(
+	ctx context.Context,
+	client graphql.Client,
+	input UnsetSecretsInput,
+) (*UnsetSecretsResponse, error) {
+	req := &graphql.Request{
+		OpName: "UnsetSecrets",
+		Query: `
+mutation UnsetSecrets ($input: UnsetSecretsInput!) {
+	unsetSecrets(input: $input) {
+		release {
+			id
+		}
+	}
+}
+`,
+		Variables: &__UnsetSecretsInput{
+			Input: input,
+		},
+	}
+	var err error
+
+	var data UnsetSecretsResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func UpdateAutoScaleConfigMutation(
 	ctx context.Context,
 	client graphql.Client,
@@ -2211,6 +2761,8 @@ query VolumeQuery ($app: String, $internal: String!) {
 			internalId
 			region
 			sizeGb
+			state
+			encrypted
 		}
 	}
 }
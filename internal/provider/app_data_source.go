@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/fly-apps/terraform-provider-fly/graphql"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -30,6 +31,31 @@ type appDataSourceOutput struct {
 	Ipaddresses    []string     `tfsdk:"ipaddresses"`
 	Currentrelease types.String `tfsdk:"currentrelease"`
 	//Secrets        types.Map    `tfsdk:"secrets"`
+
+	Org              types.String                   `tfsdk:"org"`
+	Regions          []string                       `tfsdk:"regions"`
+	MachineCount     types.Int64                    `tfsdk:"machine_count"`
+	Config           types.String                   `tfsdk:"config"`
+	Releases         []appDataSourceRelease         `tfsdk:"releases"`
+	DeploymentStatus *appDataSourceDeploymentStatus `tfsdk:"deployment_status"`
+}
+
+type appDataSourceRelease struct {
+	Version   types.Int64  `tfsdk:"version"`
+	Status    types.String `tfsdk:"status"`
+	Image     types.String `tfsdk:"image"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+type appDataSourceDeploymentStatus struct {
+	Id             types.String `tfsdk:"id"`
+	Status         types.String `tfsdk:"status"`
+	Description    types.String `tfsdk:"description"`
+	Version        types.Int64  `tfsdk:"version"`
+	DesiredCount   types.Int64  `tfsdk:"desired_count"`
+	PlacedCount    types.Int64  `tfsdk:"placed_count"`
+	HealthyCount   types.Int64  `tfsdk:"healthy_count"`
+	UnhealthyCount types.Int64  `tfsdk:"unhealthy_count"`
 }
 
 func (a appDataSourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
@@ -74,6 +100,88 @@ func (a appDataSourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Di
 				Type:     types.StringType,
 				Computed: true,
 			},
+			"org": {
+				MarkdownDescription: "Slug of the org the app belongs to",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"regions": {
+				MarkdownDescription: "Regions the app runs in",
+				Computed:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+			"machine_count": {
+				MarkdownDescription: "Number of machines in the app",
+				Type:                types.Int64Type,
+				Computed:            true,
+			},
+			"config": {
+				MarkdownDescription: "App config definition as json, use `jsondecode` to read it",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"releases": {
+				MarkdownDescription: "Most recent releases, newest first",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"version": {
+						Type:     types.Int64Type,
+						Computed: true,
+					},
+					"status": {
+						Type:     types.StringType,
+						Computed: true,
+					},
+					"image": {
+						MarkdownDescription: "Image reference the release deployed",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"created_at": {
+						Type:     types.StringType,
+						Computed: true,
+					},
+				}),
+			},
+			"deployment_status": {
+				MarkdownDescription: "Status of the latest deployment, null if the app was never deployed",
+				Computed:            true,
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"id": {
+						Type:     types.StringType,
+						Computed: true,
+					},
+					"status": {
+						Type:     types.StringType,
+						Computed: true,
+					},
+					"description": {
+						Type:     types.StringType,
+						Computed: true,
+					},
+					"version": {
+						MarkdownDescription: "Release version being deployed",
+						Type:                types.Int64Type,
+						Computed:            true,
+					},
+					"desired_count": {
+						Type:     types.Int64Type,
+						Computed: true,
+					},
+					"placed_count": {
+						Type:     types.Int64Type,
+						Computed: true,
+					},
+					"healthy_count": {
+						Type:     types.Int64Type,
+						Computed: true,
+					},
+					"unhealthy_count": {
+						Type:     types.Int64Type,
+						Computed: true,
+					},
+				}),
+			},
 		},
 	}, nil
 }
@@ -101,6 +209,13 @@ func (d appDataSource) Read(ctx context.Context, req datasource.ReadRequest, res
 	queryresp, err := graphql.GetFullApp(context.Background(), *d.provider.client, appName)
 	if err != nil {
 		resp.Diagnostics.AddError("Query failed", err.Error())
+		return
+	}
+
+	config, err := json.Marshal(queryresp.App.Config.Definition)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode app config", err.Error())
+		return
 	}
 
 	a := appDataSourceOutput{
@@ -113,6 +228,38 @@ func (d appDataSource) Read(ctx context.Context, req datasource.ReadRequest, res
 		Currentrelease: types.String{Value: queryresp.App.CurrentRelease.Id},
 		Healthchecks:   []string{},
 		Ipaddresses:    []string{},
+
+		Org:          types.String{Value: queryresp.App.Organization.Slug},
+		Regions:      []string{},
+		MachineCount: types.Int64{Value: int64(queryresp.App.Machines.TotalCount)},
+		Config:       types.String{Value: string(config)},
+		Releases:     []appDataSourceRelease{},
+	}
+
+	for _, r := range queryresp.App.Regions {
+		a.Regions = append(a.Regions, r.Code)
+	}
+
+	for _, r := range queryresp.App.Releases.Nodes {
+		a.Releases = append(a.Releases, appDataSourceRelease{
+			Version:   types.Int64{Value: int64(r.Version)},
+			Status:    types.String{Value: r.Status},
+			Image:     types.String{Value: r.ImageRef},
+			CreatedAt: types.String{Value: r.CreatedAt},
+		})
+	}
+
+	if status := queryresp.App.DeploymentStatus; status.Id != "" {
+		a.DeploymentStatus = &appDataSourceDeploymentStatus{
+			Id:             types.String{Value: status.Id},
+			Status:         types.String{Value: status.Status},
+			Description:    types.String{Value: status.Description},
+			Version:        types.Int64{Value: int64(status.Version)},
+			DesiredCount:   types.Int64{Value: int64(status.DesiredCount)},
+			PlacedCount:    types.Int64{Value: int64(status.PlacedCount)},
+			HealthyCount:   types.Int64{Value: int64(status.HealthyCount)},
+			UnhealthyCount: types.Int64{Value: int64(status.UnhealthyCount)},
+		}
 	}
 
 	for _, s := range queryresp.App.HealthChecks.Nodes {
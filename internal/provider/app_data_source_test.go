@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"os"
+	"testing"
+)
+
+func TestAccFlyAppDataSource(t *testing.T) {
+	t.Parallel()
+	app := os.Getenv("FLY_TF_TEST_APP")
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyAppDataSourceConfig(app),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fly_app.testApp", "name", app),
+					resource.TestCheckResourceAttr("data.fly_app.testApp", "org", testAccOrg),
+					resource.TestCheckResourceAttrSet("data.fly_app.testApp", "machine_count"),
+					resource.TestCheckResourceAttrSet("data.fly_app.testApp", "regions.#"),
+					resource.TestCheckResourceAttrSet("data.fly_app.testApp", "config"),
+					resource.TestCheckResourceAttrSet("data.fly_app.testApp", "releases.#"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyAppDataSourceConfig(app string) string {
+	return fmt.Sprintf(`
+data "fly_app" "testApp" {
+	name = "%s"
+}
+`, app)
+}
@@ -38,15 +38,17 @@ func (ar flyAppResourceType) GetSchema(context.Context) (tfsdk.Schema, diag.Diag
 
 		Attributes: map[string]tfsdk.Attribute{
 			"name": {
-				MarkdownDescription: "Name of application",
+				MarkdownDescription: "Name of application, changing it replaces the app",
 				Required:            true,
 				Type:                types.StringType,
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.RequiresReplace()},
 			},
 			"org": {
 				Computed:            true,
 				Optional:            true,
-				MarkdownDescription: "Optional org slug to operate upon",
+				MarkdownDescription: "Optional org slug to operate upon, changing it moves the app to the new org",
 				Type:                types.StringType,
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.UseStateForUnknown()},
 			},
 			"orgid": {
 				Computed:            true,
@@ -57,11 +59,13 @@ func (ar flyAppResourceType) GetSchema(context.Context) (tfsdk.Schema, diag.Diag
 				Computed:            true,
 				MarkdownDescription: "readonly app id",
 				Type:                types.StringType,
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.UseStateForUnknown()},
 			},
 			"appurl": {
 				Computed:            true,
 				MarkdownDescription: "readonly appUrl",
 				Type:                types.StringType,
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.UseStateForUnknown()},
 			},
 			//"secrets": {
 			//	Sensitive:           true,
@@ -212,11 +216,27 @@ func (r flyAppResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	tflog.Info(ctx, fmt.Sprintf("existing: %+v, new: %+v", state, plan))
 
+	// Name changes replace the app, so the org is the only thing that can change here
 	if !plan.Org.Unknown && plan.Org.Value != state.Org.Value {
-		resp.Diagnostics.AddError("Can't mutate org of existing app", "Can't switch org"+state.Org.Value+" to "+plan.Org.Value)
-	}
-	if !plan.Name.Null && plan.Name.Value != state.Name.Value {
-		resp.Diagnostics.AddError("Can't mutate Name of existing app", "Can't switch name "+state.Name.Value+" to "+plan.Name.Value)
+		org, err := graphql.Organization(context.Background(), *r.provider.client, plan.Org.Value)
+		if err != nil {
+			resp.Diagnostics.AddError("Could not resolve organization", err.Error())
+			return
+		}
+
+		mresp, err := graphql.MoveAppMutation(context.Background(), *r.provider.client, state.Id.Value, org.Organization.Id)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to move app to org "+plan.Org.Value, err.Error())
+			return
+		}
+
+		state = flyAppResourceData{
+			Org:    types.String{Value: mresp.MoveApp.App.Organization.Slug},
+			OrgId:  types.String{Value: mresp.MoveApp.App.Organization.Id},
+			Name:   types.String{Value: mresp.MoveApp.App.Name},
+			AppUrl: types.String{Value: mresp.MoveApp.App.AppUrl},
+			Id:     types.String{Value: mresp.MoveApp.App.Id},
+		}
 	}
 
 	//if len(plan.Secrets.Elems) > 0 {
@@ -241,7 +261,8 @@ func (r flyAppResource) Update(ctx context.Context, req resource.UpdateRequest,
 	//	state.Secrets = utils.KVToTfMap(rawSecrets, types.StringType)
 	//}
 
-	resp.State.Set(ctx, state)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"os"
+	"testing"
+)
+
+const testAccOrg = "fly-terraform-ci"
+
+func TestAccFlyAppRename(t *testing.T) {
+	t.Parallel()
+	rName := "acctest-" + acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	var appID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyAppResourceConfig(rName, testAccOrg),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_app.testApp", "name", rName),
+					resource.TestCheckResourceAttr("fly_app.testApp", "org", testAccOrg),
+					testAccCheckID("fly_app.testApp", &appID),
+				),
+			},
+			{
+				Config: testFlyAppResourceConfig(rName+"-renamed", testAccOrg),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_app.testApp", "name", rName+"-renamed"),
+					testAccCheckReplaced("fly_app.testApp", &appID),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFlyAppMoveOrg(t *testing.T) {
+	moveOrg, ok := os.LookupEnv("FLY_TF_TEST_MOVE_ORG")
+	if !ok {
+		t.Skip("Need a second org to move the app to in FLY_TF_TEST_MOVE_ORG")
+	}
+	t.Parallel()
+	rName := "acctest-" + acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	var appID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyAppResourceConfig(rName, testAccOrg),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_app.testApp", "org", testAccOrg),
+					testAccCheckID("fly_app.testApp", &appID),
+				),
+			},
+			{
+				Config: testFlyAppResourceConfig(rName, moveOrg),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_app.testApp", "org", moveOrg),
+					resource.TestCheckResourceAttr("fly_app.testApp", "name", rName),
+					resource.TestCheckResourceAttrPtr("fly_app.testApp", "id", &appID),
+				),
+			},
+		},
+	})
+}
+
+func testFlyAppResourceConfig(name string, org string) string {
+	return fmt.Sprintf(`
+resource "fly_app" "testApp" {
+	name = "%s"
+	org = "%s"
+}
+`, name, org)
+}
@@ -0,0 +1,319 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/fly-apps/terraform-provider-fly/graphql"
+	"github.com/fly-apps/terraform-provider-fly/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfsdkprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+var _ tfsdkprovider.ResourceType = flyAppSecretsResourceType{}
+var _ resource.Resource = flyAppSecretsResource{}
+var _ resource.ResourceWithImportState = flyAppSecretsResource{}
+
+type flyAppSecretsResourceType struct{}
+
+type flyAppSecretsResource struct {
+	provider provider
+}
+
+type flyAppSecretsResourceData struct {
+	Id      types.String      `tfsdk:"id"`
+	App     types.String      `tfsdk:"app"`
+	Secrets map[string]string `tfsdk:"secrets"`
+	Digests types.Map         `tfsdk:"digests"`
+}
+
+func (t flyAppSecretsResourceType) GetSchema(context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Secrets of a fly app. Only the secrets set here are managed, secrets set some other way are left alone. " +
+			"The api never returns secret values, so changes made outside terraform are detected by comparing `digests` " +
+			"and only the secrets that changed are written on update. " +
+			"Secret values are stored in plaintext in terraform state: keeping them out of state takes write-only attributes, " +
+			"which the plugin framework version this provider is built on (v0.11) doesn't have. " +
+			"Keep state in a backend that encrypts it and restricts who can read it, or set secrets that must never be in " +
+			"state with `fly secrets set` instead.",
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "Name of app",
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.UseStateForUnknown()},
+			},
+			"app": {
+				MarkdownDescription: "Name of app to set the secrets on",
+				Required:            true,
+				Type:                types.StringType,
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.RequiresReplace()},
+			},
+			"secrets": {
+				MarkdownDescription: "Secret values by name, stored in plaintext in state",
+				Required:            true,
+				Sensitive:           true,
+				Type:                types.MapType{ElemType: types.StringType},
+			},
+			"digests": {
+				MarkdownDescription: "Digests of the secret values as reported by the api, by name",
+				Computed:            true,
+				Type:                types.MapType{ElemType: types.StringType},
+			},
+		},
+	}, nil
+}
+
+func (t flyAppSecretsResourceType) NewResource(ctx context.Context, in tfsdkprovider.Provider) (resource.Resource, diag.Diagnostics) {
+	provider, diags := convertProviderType(in)
+
+	return flyAppSecretsResource{
+		provider: provider,
+	}, diags
+}
+
+// appSecretDigests returns the digest of every secret set on the app
+func (sr flyAppSecretsResource) appSecretDigests(ctx context.Context, app string) (map[string]string, error) {
+	query, err := graphql.AppSecretsQuery(ctx, *sr.provider.client, app)
+	if err != nil {
+		return nil, err
+	}
+	digests := map[string]string{}
+	for _, secret := range query.App.Secrets {
+		digests[secret.Name] = secret.Digest
+	}
+	return digests, nil
+}
+
+// managedDigests narrows digests down to the secrets terraform manages
+func managedDigests(secrets map[string]string, digests map[string]string) types.Map {
+	managed := map[string]string{}
+	for name := range secrets {
+		if digest, ok := digests[name]; ok {
+			managed[name] = digest
+		}
+	}
+	return utils.KVToTfMap(managed, types.StringType)
+}
+
+func (sr flyAppSecretsResource) setSecrets(ctx context.Context, app string, secrets map[string]string) error {
+	if len(secrets) == 0 {
+		return nil
+	}
+	var names []string
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var input []graphql.SecretInput
+	for _, name := range names {
+		input = append(input, graphql.SecretInput{
+			Key:   name,
+			Value: secrets[name],
+		})
+	}
+	tflog.Info(ctx, fmt.Sprintf("Setting secrets %v on %s", names, app))
+	_, err := graphql.SetSecrets(ctx, *sr.provider.client, graphql.SetSecretsInput{
+		AppId:   app,
+		Secrets: input,
+	})
+	return err
+}
+
+func (sr flyAppSecretsResource) unsetSecrets(ctx context.Context, app string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	tflog.Info(ctx, fmt.Sprintf("Unsetting secrets %v on %s", names, app))
+	_, err := graphql.UnsetSecrets(ctx, *sr.provider.client, graphql.UnsetSecretsInput{
+		AppId: app,
+		Keys:  names,
+	})
+	return err
+}
+
+func (sr flyAppSecretsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data flyAppSecretsResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := sr.setSecrets(ctx, data.App.Value, data.Secrets)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to set secrets", err.Error())
+		return
+	}
+
+	digests, err := sr.appSecretDigests(ctx, data.App.Value)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read secrets", err.Error())
+		return
+	}
+
+	data.Id = types.String{Value: data.App.Value}
+	data.Digests = managedDigests(data.Secrets, digests)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (sr flyAppSecretsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data flyAppSecretsResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	digests, err := sr.appSecretDigests(ctx, data.App.Value)
+	var errList gqlerror.List
+	if errors.As(err, &errList) {
+		for _, err := range errList {
+			if err.Message == "Could not resolve " {
+				tflog.Info(ctx, fmt.Sprintf("App %s not found, removing its secrets from state", data.App.Value))
+				resp.State.RemoveResource(ctx)
+				return
+			}
+			resp.Diagnostics.AddError(err.Message, err.Path.String())
+		}
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Failed to read secrets", err.Error())
+		return
+	}
+
+	var priorDigests map[string]string
+	resp.Diagnostics.Append(data.Digests.ElementsAs(ctx, &priorDigests, false)...)
+
+	// Secrets unset or changed outside terraform are dropped from state, so the next plan sets them again
+	secrets := map[string]string{}
+	for name, value := range data.Secrets {
+		digest, ok := digests[name]
+		if !ok {
+			tflog.Info(ctx, fmt.Sprintf("Secret %s is no longer set on %s", name, data.App.Value))
+			continue
+		}
+		if prior, ok := priorDigests[name]; ok && prior != digest {
+			tflog.Info(ctx, fmt.Sprintf("Secret %s on %s changed outside terraform", name, data.App.Value))
+			continue
+		}
+		secrets[name] = value
+	}
+
+	data.Id = types.String{Value: data.App.Value}
+	data.Secrets = secrets
+	data.Digests = managedDigests(secrets, digests)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (sr flyAppSecretsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan flyAppSecretsResourceData
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	var state flyAppSecretsResourceData
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only changed secrets are written, every write is a new release of the app
+	changed := map[string]string{}
+	for name, value := range plan.Secrets {
+		if prior, ok := state.Secrets[name]; !ok || prior != value {
+			changed[name] = value
+		}
+	}
+	var removed []string
+	for name := range state.Secrets {
+		if _, ok := plan.Secrets[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	err := sr.setSecrets(ctx, plan.App.Value, changed)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to set secrets", err.Error())
+		return
+	}
+
+	err = sr.unsetSecrets(ctx, plan.App.Value, removed)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to unset secrets", err.Error())
+		return
+	}
+
+	digests, err := sr.appSecretDigests(ctx, plan.App.Value)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read secrets", err.Error())
+		return
+	}
+
+	plan.Id = types.String{Value: plan.App.Value}
+	plan.Digests = managedDigests(plan.Secrets, digests)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (sr flyAppSecretsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data flyAppSecretsResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var names []string
+	for name := range data.Secrets {
+		names = append(names, name)
+	}
+
+	err := sr.unsetSecrets(ctx, data.App.Value, names)
+	var errList gqlerror.List
+	if errors.As(err, &errList) {
+		for _, err := range errList {
+			// The app, and its secrets with it, is already gone
+			if err.Message == "Could not resolve " {
+				continue
+			}
+			resp.Diagnostics.AddError(err.Message, err.Path.String())
+		}
+	} else if err != nil {
+		resp.Diagnostics.AddError("Failed to unset secrets", err.Error())
+	}
+
+	// The secrets are still set, so the resource stays in state for the next destroy to try again
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// ImportState takes the app name, secret values can't be read back so the next apply sets every configured secret
+func (sr flyAppSecretsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"testing"
+)
+
+func TestAccFlyAppSecrets(t *testing.T) {
+	t.Parallel()
+	rName := "acctest-" + acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	var otherDigest string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyAppSecretsResourceConfig(rName, map[string]string{"TEST_SECRET": "first", "OTHER_SECRET": "other"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_app_secrets.testSecrets", "id", rName),
+					resource.TestCheckResourceAttr("fly_app_secrets.testSecrets", "secrets.%", "2"),
+					resource.TestCheckResourceAttr("fly_app_secrets.testSecrets", "secrets.TEST_SECRET", "first"),
+					resource.TestCheckResourceAttr("fly_app_secrets.testSecrets", "digests.%", "2"),
+					resource.TestCheckResourceAttrWith("fly_app_secrets.testSecrets", "digests.OTHER_SECRET", func(digest string) error {
+						otherDigest = digest
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testFlyAppSecretsResourceConfig(rName, map[string]string{"TEST_SECRET": "second", "OTHER_SECRET": "other"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_app_secrets.testSecrets", "secrets.TEST_SECRET", "second"),
+					resource.TestCheckResourceAttrSet("fly_app_secrets.testSecrets", "digests.TEST_SECRET"),
+					resource.TestCheckResourceAttrPtr("fly_app_secrets.testSecrets", "digests.OTHER_SECRET", &otherDigest),
+				),
+			},
+			{
+				Config: testFlyAppSecretsResourceConfig(rName, map[string]string{"TEST_SECRET": "second"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_app_secrets.testSecrets", "secrets.%", "1"),
+					resource.TestCheckNoResourceAttr("fly_app_secrets.testSecrets", "secrets.OTHER_SECRET"),
+					resource.TestCheckResourceAttr("fly_app_secrets.testSecrets", "digests.%", "1"),
+				),
+			},
+			{
+				// The api never returns secret values, an imported resource starts out managing none of them
+				ResourceName:            "fly_app_secrets.testSecrets",
+				ImportState:             true,
+				ImportStateId:           rName,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"secrets", "digests"},
+			},
+		},
+	})
+}
+
+func testFlyAppSecretsResourceConfig(name string, secrets map[string]string) string {
+	values := ""
+	for key, value := range secrets {
+		values += fmt.Sprintf("\t\t%s = %q\n", key, value)
+	}
+
+	return fmt.Sprintf(`
+resource "fly_app" "testApp" {
+	name = "%s"
+	org = "%s"
+}
+
+resource "fly_app_secrets" "testSecrets" {
+	app = fly_app.testApp.name
+	secrets = {
+%s	}
+}
+`, name, testAccOrg, values)
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fly-apps/terraform-provider-fly/graphql"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfsdkprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ tfsdkprovider.DataSourceType = appsDataSourceType{}
+var _ datasource.DataSource = appsDataSource{}
+
+type appsDataSourceType struct{}
+
+// Matches getSchema
+type appsDataSourceOutput struct {
+	Org        types.String        `tfsdk:"org"`
+	NamePrefix types.String        `tfsdk:"name_prefix"`
+	Apps       []appsDataSourceApp `tfsdk:"apps"`
+}
+
+type appsDataSourceApp struct {
+	Id       types.String `tfsdk:"id"`
+	Name     types.String `tfsdk:"name"`
+	Org      types.String `tfsdk:"org"`
+	Status   types.String `tfsdk:"status"`
+	Deployed types.Bool   `tfsdk:"deployed"`
+	Hostname types.String `tfsdk:"hostname"`
+}
+
+func (a appsDataSourceType) GetSchema(context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Apps the token has access to, optionally filtered by org and name prefix",
+		Attributes: map[string]tfsdk.Attribute{
+			"org": {
+				MarkdownDescription: "Only list apps in the org with this slug",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"name_prefix": {
+				MarkdownDescription: "Only list apps whose name starts with this prefix",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"apps": {
+				MarkdownDescription: "Matching apps",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"id": {
+						MarkdownDescription: "app id",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"name": {
+						MarkdownDescription: "app name",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"org": {
+						MarkdownDescription: "Slug of the org the app belongs to",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"status": {
+						MarkdownDescription: "app status",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"deployed": {
+						MarkdownDescription: "Whether the app has been deployed",
+						Computed:            true,
+						Type:                types.BoolType,
+					},
+					"hostname": {
+						MarkdownDescription: "app hostname",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (a appsDataSourceType) NewDataSource(_ context.Context, in tfsdkprovider.Provider) (datasource.DataSource, diag.Diagnostics) {
+	provider, diags := convertProviderType(in)
+
+	return appsDataSource{
+		provider: provider,
+	}, diags
+}
+
+func (d appsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data appsDataSourceOutput
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Apps = make([]appsDataSourceApp, 0)
+
+	after := ""
+	for {
+		query, err := graphql.AppsQuery(ctx, *d.provider.client, after)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to list apps", err.Error())
+			return
+		}
+
+		for _, app := range query.Apps.Nodes {
+			if data.Org.Value != "" && app.Organization.Slug != data.Org.Value {
+				continue
+			}
+			if !strings.HasPrefix(app.Name, data.NamePrefix.Value) {
+				continue
+			}
+			data.Apps = append(data.Apps, appsDataSourceApp{
+				Id:       types.String{Value: app.Id},
+				Name:     types.String{Value: app.Name},
+				Org:      types.String{Value: app.Organization.Slug},
+				Status:   types.String{Value: app.Status},
+				Deployed: types.Bool{Value: app.Deployed},
+				Hostname: types.String{Value: app.Hostname},
+			})
+		}
+
+		if !query.Apps.PageInfo.HasNextPage {
+			break
+		}
+		after = query.Apps.PageInfo.EndCursor
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"os"
+	"testing"
+)
+
+func TestAccFlyAppsDataSource(t *testing.T) {
+	t.Parallel()
+	app := os.Getenv("FLY_TF_TEST_APP")
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyAppsDataSourceConfig(testAccOrg, app),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.fly_apps.testApps", "apps.*", map[string]string{
+						"name": app,
+						"org":  testAccOrg,
+					}),
+				),
+			},
+			{
+				Config: testFlyAppsDataSourceConfig(testAccOrg, "acctest-no-app-has-this-prefix-"),
+				Check:  resource.TestCheckResourceAttr("data.fly_apps.testApps", "apps.#", "0"),
+			},
+		},
+	})
+}
+
+func testFlyAppsDataSourceConfig(org string, namePrefix string) string {
+	return fmt.Sprintf(`
+data "fly_apps" "testApps" {
+	org = "%s"
+	name_prefix = "%s"
+}
+`, org, namePrefix)
+}
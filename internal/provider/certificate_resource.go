@@ -132,12 +132,16 @@ func (cr flyCertResource) Read(ctx context.Context, req resource.ReadRequest, re
 	if errors.As(err, &errList) {
 		for _, err := range errList {
 			if err.Message == "Could not resolve " {
+				tflog.Info(ctx, fmt.Sprintf("Certificate %s not found, removing it from state", hostname))
+				resp.State.RemoveResource(ctx)
 				return
 			}
 			resp.Diagnostics.AddError(err.Message, err.Path.String())
 		}
+		return
 	} else if err != nil {
 		resp.Diagnostics.AddError("Read: query failed", err.Error())
+		return
 	}
 
 	data = flyCertResourceData{
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	providerGraphql "github.com/fly-apps/terraform-provider-fly/graphql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"os"
+	"testing"
+)
+
+func TestAccFlyCertDeletedOutOfBand(t *testing.T) {
+	t.Parallel()
+	app := os.Getenv("FLY_TF_TEST_APP")
+	hostname := "acctest-" + acctest.RandStringFromCharSet(10, acctest.CharSetAlpha) + ".example.com"
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyCertResourceConfig(app, hostname),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_cert.testCert", "hostname", hostname),
+					resource.TestCheckResourceAttrSet("fly_cert.testCert", "id"),
+				),
+			},
+			{
+				// A certificate deleted outside terraform is dropped from state, so the plan adds it again. The
+				// certificate keeps its hostname, the plan is what shows it was gone.
+				PreConfig: func() {
+					_, err := providerGraphql.DeleteCertificate(context.Background(), testAccGraphqlClient(), app, hostname)
+					if err != nil {
+						t.Fatalf("Failed to delete certificate for %s: %s", hostname, err)
+					}
+				},
+				Config:             testFlyCertResourceConfig(app, hostname),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testFlyCertResourceConfig(app, hostname),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_cert.testCert", "hostname", hostname),
+					resource.TestCheckResourceAttrSet("fly_cert.testCert", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyCertResourceConfig(app string, hostname string) string {
+	return fmt.Sprintf(`
+resource "fly_cert" "testCert" {
+	app = "%s"
+	hostname = "%s"
+}
+`, app, hostname)
+}
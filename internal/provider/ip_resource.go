@@ -125,14 +125,17 @@ func (ir flyIpResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	var errList gqlerror.List
 	if errors.As(err, &errList) {
 		for _, err := range errList {
-			tflog.Info(ctx, "IN HERE")
 			if err.Message == "Could not resolve " {
+				tflog.Info(ctx, fmt.Sprintf("IP %s not found, removing it from state", addr))
+				resp.State.RemoveResource(ctx)
 				return
 			}
 			resp.Diagnostics.AddError(err.Message, err.Path.String())
 		}
+		return
 	} else if err != nil {
 		resp.Diagnostics.AddError("Read: query failed", err.Error())
+		return
 	}
 
 	data = flyIpResourceData{
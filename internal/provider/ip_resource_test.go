@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	providerGraphql "github.com/fly-apps/terraform-provider-fly/graphql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"os"
+	"testing"
+)
+
+func TestAccFlyIpDeletedOutOfBand(t *testing.T) {
+	t.Parallel()
+	var ipID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyIpResourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("fly_ip.testIp", "address"),
+					testAccCheckID("fly_ip.testIp", &ipID),
+				),
+			},
+			{
+				// An address released outside terraform is dropped from state and allocated again
+				PreConfig: func() {
+					_, err := providerGraphql.ReleaseIpAddress(context.Background(), testAccGraphqlClient(), ipID)
+					if err != nil {
+						t.Fatalf("Failed to release ip %s: %s", ipID, err)
+					}
+				},
+				Config: testFlyIpResourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("fly_ip.testIp", "address"),
+					testAccCheckReplaced("fly_ip.testIp", &ipID),
+				),
+			},
+		},
+	})
+}
+
+func testFlyIpResourceConfig() string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+resource "fly_ip" "testIp" {
+	app = "%s"
+	type = "v6"
+}
+`, app)
+}
@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/fly-apps/terraform-provider-fly/internal/utils"
+	"github.com/fly-apps/terraform-provider-fly/pkg/apiv1"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfsdkprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ tfsdkprovider.DataSourceType = machineDataSourceType{}
+var _ datasource.DataSource = machineDataSource{}
+
+type machineDataSourceType struct{}
+
+// Matches getSchema, which is the fly_machine resource schema without resourceOnlyMachineAttributes
+type machineDataSourceOutput struct {
+	Name       types.String `tfsdk:"name"`
+	Region     types.String `tfsdk:"region"`
+	Id         types.String `tfsdk:"id"`
+	PrivateIP  types.String `tfsdk:"privateip"`
+	App        types.String `tfsdk:"app"`
+	Image      types.String `tfsdk:"image"`
+	Cpus       types.Int64  `tfsdk:"cpus"`
+	MemoryMb   types.Int64  `tfsdk:"memorymb"`
+	CpuType    types.String `tfsdk:"cputype"`
+	Env        types.Map    `tfsdk:"env"`
+	Cmd        []string     `tfsdk:"cmd"`
+	Entrypoint []string     `tfsdk:"entrypoint"`
+	Exec       []string     `tfsdk:"exec"`
+
+	DesiredState types.String `tfsdk:"desired_state"`
+	Cordoned     types.Bool   `tfsdk:"cordoned"`
+
+	Mounts   []TfMachineMount   `tfsdk:"mounts"`
+	Services []TfService        `tfsdk:"services"`
+	Checks   map[string]TfCheck `tfsdk:"checks"`
+
+	Restart     types.Object `tfsdk:"restart"`
+	AutoDestroy types.Bool   `tfsdk:"auto_destroy"`
+	Schedule    types.String `tfsdk:"schedule"`
+	Standbys    []string     `tfsdk:"standbys"`
+
+	Metadata types.Map    `tfsdk:"metadata"`
+	ImageRef types.Object `tfsdk:"image_ref"`
+
+	Files     []TfFile    `tfsdk:"files"`
+	Processes []TfProcess `tfsdk:"processes"`
+}
+
+// Attributes of the fly_machine resource that control how terraform manages the machine rather than describe it
+var resourceOnlyMachineAttributes = []string{
+	"wait_for_checks", "update_strategy", "pin_image_digest", "image_digest", "stop_signal", "stop_timeout", "force_destroy",
+}
+
+var (
+	singleNesting = tfsdk.SingleNestedAttributes(nil).GetNestingMode()
+	listNesting   = tfsdk.ListNestedAttributes(nil).GetNestingMode()
+	setNesting    = tfsdk.SetNestedAttributes(nil).GetNestingMode()
+	mapNesting    = tfsdk.MapNestedAttributes(nil).GetNestingMode()
+)
+
+// computedAttribute turns a resource attribute into a read only one, including any attributes nested in it
+func computedAttribute(attribute tfsdk.Attribute) tfsdk.Attribute {
+	attribute.Required = false
+	attribute.Optional = false
+	attribute.Computed = true
+	attribute.Validators = nil
+	attribute.PlanModifiers = nil
+
+	if attribute.Attributes == nil {
+		return attribute
+	}
+
+	nested := map[string]tfsdk.Attribute{}
+	for name, a := range attribute.Attributes.GetAttributes() {
+		nested[name] = computedAttribute(a.(tfsdk.Attribute))
+	}
+	switch attribute.Attributes.GetNestingMode() {
+	case singleNesting:
+		attribute.Attributes = tfsdk.SingleNestedAttributes(nested)
+	case listNesting:
+		attribute.Attributes = tfsdk.ListNestedAttributes(nested)
+	case setNesting:
+		attribute.Attributes = tfsdk.SetNestedAttributes(nested)
+	case mapNesting:
+		attribute.Attributes = tfsdk.MapNestedAttributes(nested)
+	}
+	return attribute
+}
+
+func (m machineDataSourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	resourceSchema, diags := flyMachineResourceType{}.GetSchema(ctx)
+
+	attributes := map[string]tfsdk.Attribute{}
+	for name, attribute := range resourceSchema.Attributes {
+		attributes[name] = computedAttribute(attribute)
+	}
+	for _, name := range resourceOnlyMachineAttributes {
+		delete(attributes, name)
+	}
+
+	attributes["app"] = tfsdk.Attribute{
+		MarkdownDescription: "fly app",
+		Required:            true,
+		Type:                types.StringType,
+	}
+	attributes["id"] = tfsdk.Attribute{
+		MarkdownDescription: "machine id, either id or name has to be set",
+		Optional:            true,
+		Computed:            true,
+		Type:                types.StringType,
+	}
+	attributes["name"] = tfsdk.Attribute{
+		MarkdownDescription: "machine name, either id or name has to be set",
+		Optional:            true,
+		Computed:            true,
+		Type:                types.StringType,
+	}
+
+	desiredState := attributes["desired_state"]
+	desiredState.MarkdownDescription = "Whether the machine is `started`, `stopped` or `suspended`"
+	attributes["desired_state"] = desiredState
+
+	return tfsdk.Schema{
+		MarkdownDescription: "Fly machine data source, looks up a machine by id or name",
+		Attributes:          attributes,
+	}, diags
+}
+
+func (m machineDataSourceType) NewDataSource(_ context.Context, in tfsdkprovider.Provider) (datasource.DataSource, diag.Diagnostics) {
+	provider, diags := convertProviderType(in)
+
+	return machineDataSource{
+		provider: provider,
+	}, diags
+}
+
+// findMachine reads the machine by id, or looks it up by name when no id is given
+func findMachine(machineAPI *apiv1.MachineAPI, app string, id string, name string, res *apiv1.MachineResponse) error {
+	if id != "" {
+		return machineAPI.ReadMachine(app, id, res)
+	}
+
+	machines, err := machineAPI.ListMachines(app, apiv1.MachineListFilters{})
+	if err != nil {
+		return err
+	}
+	var matches []apiv1.MachineResponse
+	for _, machine := range machines {
+		if machine.Name == name {
+			matches = append(matches, machine)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return errors.New(fmt.Sprintf("no machine named %s in app %s", name, app))
+	case 1:
+		*res = matches[0]
+		return nil
+	default:
+		return errors.New(fmt.Sprintf("%d machines are named %s in app %s, look the machine up by id instead", len(matches), name, app))
+	}
+}
+
+func (m machineDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data machineDataSourceOutput
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Id.Value == "" && data.Name.Value == "" {
+		resp.Diagnostics.AddError("Missing machine id or name", "Set either id or name to look up a machine")
+		return
+	}
+
+	machineAPI := apiv1.NewMachineAPI(m.provider.httpClient, m.provider.httpEndpoint)
+
+	var machine apiv1.MachineResponse
+	err := findMachine(machineAPI, data.App.Value, data.Id.Value, data.Name.Value, &machine)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read machine", err.Error())
+		return
+	}
+
+	tfservices := ServicesToTfServices(machine.Config.Services)
+	if len(tfservices) == 0 {
+		tfservices = nil
+	}
+
+	tfprocesses := ProcessesToTfProcesses(machine.Config.Processes)
+	if len(tfprocesses) == 0 {
+		tfprocesses = nil
+	}
+
+	data = machineDataSourceOutput{
+		Name:       types.String{Value: machine.Name},
+		Id:         types.String{Value: machine.ID},
+		Region:     types.String{Value: machine.Region},
+		App:        types.String{Value: data.App.Value},
+		PrivateIP:  types.String{Value: machine.PrivateIP},
+		Image:      types.String{Value: machine.Config.Image},
+		Cpus:       types.Int64{Value: int64(machine.Config.Guest.Cpus)},
+		MemoryMb:   types.Int64{Value: int64(machine.Config.Guest.MemoryMb)},
+		CpuType:    types.String{Value: machine.Config.Guest.CPUKind},
+		Cmd:        machine.Config.Init.Cmd,
+		Entrypoint: machine.Config.Init.Entrypoint,
+		Exec:       machine.Config.Init.Exec,
+		Env:        utils.KVToTfMap(machine.Config.Env, types.StringType),
+		Services:   tfservices,
+
+		DesiredState: types.String{Value: MachineStateToDesiredState(machine.State)},
+		Cordoned:     CordonedToTfCordoned(machine.Cordoned, types.Bool{Value: false}),
+
+		Checks: ChecksToTfChecks(machine.Config.Checks, nil),
+
+		Restart:     RestartToTfRestart(machine.Config.Restart),
+		AutoDestroy: types.Bool{Value: machine.Config.AutoDestroy},
+		Schedule:    optionalString(machine.Config.Schedule),
+		Standbys:    machine.Config.Standbys,
+
+		Metadata: utils.KVToTfMap(machine.Config.Metadata, types.StringType),
+		ImageRef: ImageRefToTfImageRef(machine.ImageRef),
+
+		Files:     FilesToTfFiles(machine.Config.Files, nil),
+		Processes: tfprocesses,
+	}
+
+	for _, m := range machine.Config.Mounts {
+		data.Mounts = append(data.Mounts, TfMachineMount{
+			Encrypted: types.Bool{Value: m.Encrypted},
+			Path:      types.String{Value: m.Path},
+			SizeGb:    types.Int64{Value: int64(m.SizeGb)},
+			Volume:    types.String{Value: m.Volume},
+		})
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fly-apps/terraform-provider-fly/internal/provider/validators"
+	"github.com/fly-apps/terraform-provider-fly/pkg/apiv1"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfsdkprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ tfsdkprovider.ResourceType = flyMachineExecResourceType{}
+var _ resource.Resource = flyMachineExecResource{}
+
+type flyMachineExecResourceType struct{}
+
+type flyMachineExecResource struct {
+	provider provider
+}
+
+type flyMachineExecResourceData struct {
+	Id        types.String `tfsdk:"id"`
+	App       types.String `tfsdk:"app"`
+	MachineId types.String `tfsdk:"machine_id"`
+	Command   []string     `tfsdk:"command"`
+	Triggers  types.Map    `tfsdk:"triggers"`
+	Timeout   types.String `tfsdk:"timeout"`
+
+	StdOut   types.String `tfsdk:"stdout"`
+	StdErr   types.String `tfsdk:"stderr"`
+	ExitCode types.Int64  `tfsdk:"exit_code"`
+}
+
+func (t flyMachineExecResourceType) GetSchema(context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Runs a command in an existing machine when created, and again whenever `triggers` change. " +
+			"A non zero exit code doesn't fail the apply, check `exit_code` in a `postcondition` for that. " +
+			"Destroying the resource only removes it from state.",
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "ID of this run of the command",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"app": {
+				MarkdownDescription: "Name of app the machine belongs to",
+				Required:            true,
+				Type:                types.StringType,
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.RequiresReplace()},
+			},
+			"machine_id": {
+				MarkdownDescription: "ID of the machine to run the command in, it has to be started",
+				Required:            true,
+				Type:                types.StringType,
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.RequiresReplace()},
+			},
+			"command": {
+				MarkdownDescription: "Command to run, like `[\"bin/rails\", \"db:migrate\"]`",
+				Required:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.RequiresReplace()},
+			},
+			"triggers": {
+				MarkdownDescription: "Arbitrary values that run the command again when they change, like the image of the machine",
+				Optional:            true,
+				Type:                types.MapType{ElemType: types.StringType},
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.RequiresReplace()},
+			},
+			"timeout": {
+				MarkdownDescription: "How long the command may run, like `30s` or `10m`. Defaults to `5m`",
+				Optional:            true,
+				Type:                types.StringType,
+				Validators:          []tfsdk.AttributeValidator{validators.Duration()},
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.RequiresReplace()},
+			},
+			"stdout": {
+				MarkdownDescription: "Standard output of the command",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"stderr": {
+				MarkdownDescription: "Standard error of the command",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"exit_code": {
+				MarkdownDescription: "Exit code of the command",
+				Computed:            true,
+				Type:                types.Int64Type,
+			},
+		},
+	}, nil
+}
+
+func (t flyMachineExecResourceType) NewResource(ctx context.Context, in tfsdkprovider.Provider) (resource.Resource, diag.Diagnostics) {
+	provider, diags := convertProviderType(in)
+
+	return flyMachineExecResource{
+		provider: provider,
+	}, diags
+}
+
+func (mr flyMachineExecResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	_, err := validateOpenTunnel(mr.provider)
+	if err != nil {
+		resp.Diagnostics.AddError("fly wireguard tunnel must be open", err.Error())
+		return
+	}
+
+	var data flyMachineExecResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	machineAPI := apiv1.NewMachineAPI(mr.provider.httpClient, mr.provider.httpEndpoint)
+
+	timeout := parseTimeout(data.Timeout)
+	tflog.Info(ctx, fmt.Sprintf("Running %v in machine %s", data.Command, data.MachineId.Value))
+
+	res, err := machineAPI.ExecMachine(data.App.Value, data.MachineId.Value, apiv1.MachineExecRequest{
+		Command: data.Command,
+		Timeout: int(timeout.Seconds()),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to run command in machine", err.Error())
+		return
+	}
+
+	data.Id = types.String{Value: fmt.Sprintf("%s-%d", data.MachineId.Value, time.Now().UnixNano())}
+	data.StdOut = types.String{Value: res.StdOut}
+	data.StdErr = types.String{Value: res.StdErr}
+	data.ExitCode = types.Int64{Value: res.ExitCode}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read keeps the output of the last run, there's nothing to read back from the api
+func (mr flyMachineExecResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data flyMachineExecResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update is never called, every attribute either replaces the resource or is computed
+func (mr flyMachineExecResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+}
+
+func (mr flyMachineExecResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
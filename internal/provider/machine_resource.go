@@ -2,17 +2,23 @@ package provider
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/fly-apps/terraform-provider-fly/internal/provider/validators"
 	"github.com/fly-apps/terraform-provider-fly/internal/utils"
 	"github.com/fly-apps/terraform-provider-fly/pkg/apiv1"
+	"github.com/fly-apps/terraform-provider-fly/pkg/registry"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	tfsdkprovider "github.com/hashicorp/terraform-plugin-framework/provider"
@@ -21,6 +27,8 @@ import (
 var _ tfsdkprovider.ResourceType = flyMachineResourceType{}
 var _ resource.Resource = flyMachineResource{}
 var _ resource.ResourceWithImportState = flyMachineResource{}
+var _ resource.ResourceWithModifyPlan = flyMachineResource{}
+var _ resource.ResourceWithValidateConfig = flyMachineResource{}
 
 type flyMachineResourceType struct{}
 
@@ -29,15 +37,91 @@ type flyMachineResource struct {
 	endpoint string
 }
 
+type TfTLSOptions struct {
+	Alpn     []string `tfsdk:"alpn"`
+	Versions []string `tfsdk:"versions"`
+}
+
+type TfHTTPOptions struct {
+	Compress        types.Bool        `tfsdk:"compress"`
+	ResponseHeaders map[string]string `tfsdk:"response_headers"`
+}
+
+type TfProxyProtoOptions struct {
+	Version types.String `tfsdk:"version"`
+}
+
 type TfPort struct {
-	Port     types.Int64    `tfsdk:"port"`
-	Handlers []types.String `tfsdk:"handlers"`
+	Port              types.Int64          `tfsdk:"port"`
+	Handlers          []types.String       `tfsdk:"handlers"`
+	ForceHttps        types.Bool           `tfsdk:"force_https"`
+	TLSOptions        *TfTLSOptions        `tfsdk:"tls_options"`
+	HTTPOptions       *TfHTTPOptions       `tfsdk:"http_options"`
+	ProxyProtoOptions *TfProxyProtoOptions `tfsdk:"proxy_proto_options"`
+}
+
+type TfConcurrency struct {
+	Type      types.String `tfsdk:"type"`
+	HardLimit types.Int64  `tfsdk:"hard_limit"`
+	SoftLimit types.Int64  `tfsdk:"soft_limit"`
 }
 
 type TfService struct {
-	Ports        []TfPort     `tfsdk:"ports"`
-	Protocol     types.String `tfsdk:"protocol"`
-	InternalPort types.Int64  `tfsdk:"internal_port"`
+	Ports        []TfPort       `tfsdk:"ports"`
+	Protocol     types.String   `tfsdk:"protocol"`
+	InternalPort types.Int64    `tfsdk:"internal_port"`
+	Autostop     types.Bool     `tfsdk:"autostop"`
+	Autostart    types.Bool     `tfsdk:"autostart"`
+	Concurrency  *TfConcurrency `tfsdk:"concurrency"`
+}
+
+type TfCheck struct {
+	Type        types.String      `tfsdk:"type"`
+	Port        types.Int64       `tfsdk:"port"`
+	Interval    types.String      `tfsdk:"interval"`
+	Timeout     types.String      `tfsdk:"timeout"`
+	GracePeriod types.String      `tfsdk:"grace_period"`
+	Method      types.String      `tfsdk:"method"`
+	Path        types.String      `tfsdk:"path"`
+	Protocol    types.String      `tfsdk:"protocol"`
+	Headers     map[string]string `tfsdk:"headers"`
+}
+
+type TfRestart struct {
+	Policy     types.String `tfsdk:"policy"`
+	MaxRetries types.Int64  `tfsdk:"max_retries"`
+}
+
+var restartAttrTypes = map[string]attr.Type{
+	"policy":      types.StringType,
+	"max_retries": types.Int64Type,
+}
+
+var imageRefAttrTypes = map[string]attr.Type{
+	"registry":   types.StringType,
+	"repository": types.StringType,
+	"tag":        types.StringType,
+	"digest":     types.StringType,
+	"labels":     types.MapType{ElemType: types.StringType},
+}
+
+const (
+	updateStrategyInPlace   = "in-place"
+	updateStrategyBlueGreen = "blue-green"
+)
+
+// Attributes that aren't part of the machine config. A plan that only changes these is applied to the running machine,
+// without the config update that would restart it or, with blue-green updates, replace it. The teardown settings,
+// update settings and timeouts are only read by the provider itself, so they go straight into state.
+var lifecycleAttributes = map[string]bool{
+	"cordoned":        true,
+	"desired_state":   true,
+	"stop_signal":     true,
+	"stop_timeout":    true,
+	"force_destroy":   true,
+	"timeouts":        true,
+	"wait_for_checks": true,
+	"update_strategy": true,
 }
 
 type flyMachineResourceData struct {
@@ -55,8 +139,52 @@ type flyMachineResourceData struct {
 	Entrypoint []string     `tfsdk:"entrypoint"`
 	Exec       []string     `tfsdk:"exec"`
 
-	Mounts   []TfMachineMount `tfsdk:"mounts"`
-	Services []TfService      `tfsdk:"services"`
+	DesiredState types.String `tfsdk:"desired_state"`
+	Cordoned     types.Bool   `tfsdk:"cordoned"`
+
+	Mounts        []TfMachineMount   `tfsdk:"mounts"`
+	Services      []TfService        `tfsdk:"services"`
+	Checks        map[string]TfCheck `tfsdk:"checks"`
+	WaitForChecks types.Bool         `tfsdk:"wait_for_checks"`
+
+	UpdateStrategy types.String `tfsdk:"update_strategy"`
+
+	StopSignal   types.String `tfsdk:"stop_signal"`
+	StopTimeout  types.String `tfsdk:"stop_timeout"`
+	ForceDestroy types.Bool   `tfsdk:"force_destroy"`
+
+	Timeouts []TfTimeouts `tfsdk:"timeouts"`
+
+	// Restart is a types.Object rather than a *TfRestart because it is computed and can be unknown in the plan
+	Restart     types.Object `tfsdk:"restart"`
+	AutoDestroy types.Bool   `tfsdk:"auto_destroy"`
+	Schedule    types.String `tfsdk:"schedule"`
+	Standbys    []string     `tfsdk:"standbys"`
+
+	Metadata types.Map    `tfsdk:"metadata"`
+	ImageRef types.Object `tfsdk:"image_ref"`
+
+	PinImageDigest types.Bool   `tfsdk:"pin_image_digest"`
+	ImageDigest    types.String `tfsdk:"image_digest"`
+
+	Files     []TfFile    `tfsdk:"files"`
+	Processes []TfProcess `tfsdk:"processes"`
+}
+
+type TfFile struct {
+	GuestPath     types.String `tfsdk:"guest_path"`
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	SecretName    types.String `tfsdk:"secret_name"`
+}
+
+type TfProcess struct {
+	Name       types.String      `tfsdk:"name"`
+	Entrypoint []string          `tfsdk:"entrypoint"`
+	Cmd        []string          `tfsdk:"cmd"`
+	Exec       []string          `tfsdk:"exec"`
+	Env        map[string]string `tfsdk:"env"`
+	User       types.String      `tfsdk:"user"`
 }
 
 type TfMachineMount struct {
@@ -155,6 +283,63 @@ func (mr flyMachineResourceType) GetSchema(context.Context) (tfsdk.Schema, diag.
 				Computed:            true,
 				Type:                types.MapType{ElemType: types.StringType},
 			},
+			"metadata": {
+				MarkdownDescription: "Optional metadata to tag the machine with, keys and values must be strings",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.MapType{ElemType: types.StringType},
+			},
+			"pin_image_digest": {
+				MarkdownDescription: "Resolve `image` to a digest while planning and deploy that digest, so a tag that has moved shows up as an update",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"image_digest": {
+				MarkdownDescription: "Digest `image` was pinned to when `pin_image_digest` is set",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"image_ref": {
+				MarkdownDescription: "The image reference the orchestrator resolved `image` to",
+				Computed:            true,
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"registry": {
+						Computed: true,
+						Type:     types.StringType,
+					},
+					"repository": {
+						Computed: true,
+						Type:     types.StringType,
+					},
+					"tag": {
+						Computed: true,
+						Type:     types.StringType,
+					},
+					"digest": {
+						Computed: true,
+						Type:     types.StringType,
+					},
+					"labels": {
+						Computed: true,
+						Type:     types.MapType{ElemType: types.StringType},
+					},
+				}),
+			},
+			"desired_state": {
+				MarkdownDescription: "State the machine should be kept in, `started`, `stopped` or `suspended`. Suspended machines have their memory snapshotted when stopping and resume from it when started again. If unset the machine is left in whatever state the orchestrator puts it in",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					validators.StringOneOf("started", "stopped", "suspended"),
+				},
+			},
+			"cordoned": {
+				MarkdownDescription: "Take the machine out of fly-proxy load balancing without stopping it",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+			},
 			"mounts": {
 				MarkdownDescription: "Volume mounts",
 				Optional:            true,
@@ -181,6 +366,70 @@ func (mr flyMachineResourceType) GetSchema(context.Context) (tfsdk.Schema, diag.
 					},
 				}),
 			},
+			"processes": {
+				MarkdownDescription: "Processes to run in the machine. When unset the image entrypoint and cmd, or the top level overrides, are used",
+				Optional:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"name": {
+						MarkdownDescription: "Process name",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"entrypoint": {
+						MarkdownDescription: "Override of the image entrypoint",
+						Optional:            true,
+						Type:                types.ListType{ElemType: types.StringType},
+					},
+					"cmd": {
+						MarkdownDescription: "Override of the image cmd",
+						Optional:            true,
+						Type:                types.ListType{ElemType: types.StringType},
+					},
+					"exec": {
+						MarkdownDescription: "Command to run instead of entrypoint and cmd",
+						Optional:            true,
+						Type:                types.ListType{ElemType: types.StringType},
+					},
+					"env": {
+						MarkdownDescription: "Environment variables added to the machine env for this process",
+						Optional:            true,
+						Type:                types.MapType{ElemType: types.StringType},
+					},
+					"user": {
+						MarkdownDescription: "User to run the process as",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+				}),
+			},
+			"files": {
+				MarkdownDescription: "Files to write into the machine before it boots. Set exactly one of `content`, `content_base64` or `secret_name` for each file",
+				Optional:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"guest_path": {
+						MarkdownDescription: "Absolute path of the file in the machine",
+						Required:            true,
+						Type:                types.StringType,
+					},
+					"content": {
+						MarkdownDescription: "Content of the file as plain text",
+						Optional:            true,
+						Sensitive:           true,
+						Type:                types.StringType,
+					},
+					"content_base64": {
+						MarkdownDescription: "Content of the file, base64 encoded. Use this for binary files",
+						Optional:            true,
+						Sensitive:           true,
+						Type:                types.StringType,
+					},
+					"secret_name": {
+						MarkdownDescription: "Name of an app secret whose value becomes the content of the file",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+				}),
+			},
 			"services": {
 				MarkdownDescription: "services",
 				Optional:            true,
@@ -195,10 +444,63 @@ func (mr flyMachineResourceType) GetSchema(context.Context) (tfsdk.Schema, diag.
 								Type:                types.Int64Type,
 							},
 							"handlers": {
-								MarkdownDescription: "How the edge should process requests",
+								MarkdownDescription: "How the edge should process requests, eg. `tls`, `http` or `proxy_proto`",
 								Optional:            true,
 								Type:                types.ListType{ElemType: types.StringType},
 							},
+							"force_https": {
+								MarkdownDescription: "Redirect plain http requests on this port to https",
+								Optional:            true,
+								Computed:            true,
+								Type:                types.BoolType,
+							},
+							"tls_options": {
+								MarkdownDescription: "Options for the tls handler",
+								Optional:            true,
+								Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+									"alpn": {
+										MarkdownDescription: "ALPN protocols to negotiate, eg. `h2` and `http/1.1`",
+										Optional:            true,
+										Type:                types.ListType{ElemType: types.StringType},
+									},
+									"versions": {
+										MarkdownDescription: "Allowed TLS versions, eg. `TLSv1.2` and `TLSv1.3`",
+										Optional:            true,
+										Type:                types.ListType{ElemType: types.StringType},
+									},
+								}),
+							},
+							"http_options": {
+								MarkdownDescription: "Options for the http handler",
+								Optional:            true,
+								Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+									"compress": {
+										MarkdownDescription: "Compress responses",
+										Optional:            true,
+										Computed:            true,
+										Type:                types.BoolType,
+									},
+									"response_headers": {
+										MarkdownDescription: "Headers to add to every response",
+										Optional:            true,
+										Type:                types.MapType{ElemType: types.StringType},
+									},
+								}),
+							},
+							"proxy_proto_options": {
+								MarkdownDescription: "Options for the proxy_proto handler",
+								Optional:            true,
+								Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+									"version": {
+										MarkdownDescription: "Proxy protocol version, `v1` or `v2`",
+										Optional:            true,
+										Type:                types.StringType,
+										Validators: []tfsdk.AttributeValidator{
+											validators.StringOneOf("v1", "v2"),
+										},
+									},
+								}),
+							},
 						}),
 					},
 					"protocol": {
@@ -211,8 +513,177 @@ func (mr flyMachineResourceType) GetSchema(context.Context) (tfsdk.Schema, diag.
 						Required:            true,
 						Type:                types.Int64Type,
 					},
+					"autostop": {
+						MarkdownDescription: "Let the proxy stop the machine when it has no traffic",
+						Optional:            true,
+						Computed:            true,
+						Type:                types.BoolType,
+					},
+					"autostart": {
+						MarkdownDescription: "Let the proxy start the machine when a request comes in",
+						Optional:            true,
+						Computed:            true,
+						Type:                types.BoolType,
+					},
+					"concurrency": {
+						MarkdownDescription: "Load balancing concurrency limits",
+						Optional:            true,
+						Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+							"type": {
+								MarkdownDescription: "What to count towards the limits, `connections` or `requests`",
+								Required:            true,
+								Type:                types.StringType,
+								Validators: []tfsdk.AttributeValidator{
+									validators.StringOneOf("connections", "requests"),
+								},
+							},
+							"soft_limit": {
+								MarkdownDescription: "Load at which the proxy starts preferring other machines",
+								Optional:            true,
+								Type:                types.Int64Type,
+							},
+							"hard_limit": {
+								MarkdownDescription: "Load at which the proxy stops sending traffic to the machine",
+								Optional:            true,
+								Type:                types.Int64Type,
+							},
+						}),
+					},
+				}),
+			},
+			"checks": {
+				MarkdownDescription: "Health checks keyed by check name",
+				Optional:            true,
+				Attributes: tfsdk.MapNestedAttributes(map[string]tfsdk.Attribute{
+					"type": {
+						MarkdownDescription: "Kind of check, `tcp` or `http`. Script checks aren't supported, the machines api only runs tcp and http checks",
+						Required:            true,
+						Type:                types.StringType,
+						Validators: []tfsdk.AttributeValidator{
+							validators.StringOneOf("tcp", "http"),
+						},
+					},
+					"port": {
+						MarkdownDescription: "Port to check on the machine",
+						Required:            true,
+						Type:                types.Int64Type,
+					},
+					"interval": {
+						MarkdownDescription: "Time between checks, eg. `15s`",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"timeout": {
+						MarkdownDescription: "Time to wait for a check to respond before it is considered failed, eg. `10s`",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"grace_period": {
+						MarkdownDescription: "Time to wait after the machine starts before running checks, eg. `30s`",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"method": {
+						MarkdownDescription: "HTTP method for http checks",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"path": {
+						MarkdownDescription: "HTTP path for http checks",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"protocol": {
+						MarkdownDescription: "`http` or `https` for http checks",
+						Optional:            true,
+						Type:                types.StringType,
+					},
+					"headers": {
+						MarkdownDescription: "HTTP headers to send with http checks",
+						Optional:            true,
+						Type:                types.MapType{ElemType: types.StringType},
+					},
+				}),
+			},
+			"restart": {
+				MarkdownDescription: "What the orchestrator should do when the machine exits",
+				Optional:            true,
+				Computed:            true,
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"policy": {
+						MarkdownDescription: "`no`, `on-failure` or `always`",
+						Required:            true,
+						Type:                types.StringType,
+						Validators: []tfsdk.AttributeValidator{
+							validators.StringOneOf("no", "on-failure", "always"),
+						},
+					},
+					"max_retries": {
+						MarkdownDescription: "How many times to restart a failing machine when policy is `on-failure`",
+						Optional:            true,
+						Computed:            true,
+						Type:                types.Int64Type,
+					},
 				}),
 			},
+			"auto_destroy": {
+				MarkdownDescription: "Destroy the machine once it exits",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+			},
+			"schedule": {
+				MarkdownDescription: "Run the machine on a schedule: `hourly`, `daily`, `weekly` or `monthly`. Use together with `restart` and `auto_destroy` to control what happens between runs",
+				Optional:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					validators.StringOneOf("hourly", "daily", "weekly", "monthly"),
+				},
+			},
+			"standbys": {
+				MarkdownDescription: "IDs of machines this machine is a standby for. A standby is kept stopped and only started when one of those machines fails",
+				Optional:            true,
+				Type:                types.ListType{ElemType: types.StringType},
+			},
+			"wait_for_checks": {
+				MarkdownDescription: "Wait for all health checks to pass before finishing create or update. The wait counts against the create and update timeouts. " +
+					"`blue-green` updates always wait for the checks of the replacement before destroying the old machine",
+				Optional: true,
+				Type:     types.BoolType,
+			},
+			"update_strategy": {
+				MarkdownDescription: "How changes are rolled out. `in-place` (the default) updates the existing machine. `blue-green` creates a new machine, waits for it to start and pass its health checks and only then destroys the old one, so the machine id changes on every update. `blue-green` can't be used with mounts since a volume can only be attached to one machine, " +
+					"or with `name` since the replacement exists alongside the old machine and gets a generated name",
+				Optional: true,
+				Type:     types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					validators.StringOneOf(updateStrategyInPlace, updateStrategyBlueGreen),
+				},
+			},
+			"stop_signal": {
+				MarkdownDescription: "Signal sent to the machine when it is stopped to be destroyed, defaults to the image's stop signal or `SIGINT`",
+				Optional:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					validators.StringOneOf("SIGABRT", "SIGALRM", "SIGFPE", "SIGHUP", "SIGILL", "SIGINT", "SIGKILL", "SIGPIPE", "SIGQUIT", "SIGSEGV", "SIGTERM", "SIGTRAP", "SIGUSR1", "SIGUSR2"),
+				},
+			},
+			"stop_timeout": {
+				MarkdownDescription: "How long the machine gets to shut down after `stop_signal` before it is killed, like `30s`",
+				Optional:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					validators.Duration(),
+				},
+			},
+			"force_destroy": {
+				MarkdownDescription: "Destroy the machine without stopping it first",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
 		},
 	}, nil
 }
@@ -225,58 +696,580 @@ func (mr flyMachineResourceType) NewResource(_ context.Context, in tfsdkprovider
 	}, diags
 }
 
-func (mr flyMachineResource) ValidateOpenTunnel() (bool, error) {
-	_, err := mr.provider.httpClient.R().Get(fmt.Sprintf("http://%s", mr.provider.httpEndpoint))
+func (mr flyMachineResource) ValidateOpenTunnel() (bool, error) {
+	return validateOpenTunnel(mr.provider)
+}
+
+// validateOpenTunnel checks that the machines api can be reached, which takes the wireguard tunnel or a proxy
+func validateOpenTunnel(p provider) (bool, error) {
+	_, err := p.httpClient.R().Get(fmt.Sprintf("http://%s", p.httpEndpoint))
+	if err != nil {
+		return false, errors.New("can't connect to the api, is the tunnel open? :)")
+	}
+	return true, nil
+}
+
+func TfServicesToServices(input []TfService) []apiv1.Service {
+	services := make([]apiv1.Service, 0)
+	for _, s := range input {
+		var ports []apiv1.Port
+		for _, j := range s.Ports {
+			var handlers []string
+			for _, k := range j.Handlers {
+				handlers = append(handlers, k.Value)
+			}
+			port := apiv1.Port{
+				Port:       j.Port.Value,
+				Handlers:   handlers,
+				ForceHttps: boolPointer(j.ForceHttps),
+			}
+			if j.TLSOptions != nil {
+				port.TLSOptions = &apiv1.TLSOptions{
+					Alpn:     j.TLSOptions.Alpn,
+					Versions: j.TLSOptions.Versions,
+				}
+			}
+			if j.HTTPOptions != nil {
+				port.HTTPOptions = &apiv1.HTTPOptions{
+					Compress: boolPointer(j.HTTPOptions.Compress),
+				}
+				if len(j.HTTPOptions.ResponseHeaders) > 0 {
+					headers := map[string]interface{}{}
+					for k, v := range j.HTTPOptions.ResponseHeaders {
+						headers[k] = v
+					}
+					port.HTTPOptions.Response = &apiv1.HTTPResponseOptions{Headers: headers}
+				}
+			}
+			if j.ProxyProtoOptions != nil {
+				port.ProxyProtoOptions = &apiv1.ProxyProtoOptions{
+					Version: j.ProxyProtoOptions.Version.Value,
+				}
+			}
+			ports = append(ports, port)
+		}
+		service := apiv1.Service{
+			Ports:        ports,
+			Protocol:     s.Protocol.Value,
+			InternalPort: s.InternalPort.Value,
+			Autostop:     boolPointer(s.Autostop),
+			Autostart:    boolPointer(s.Autostart),
+		}
+		if s.Concurrency != nil {
+			service.Concurrency = &apiv1.ServiceConcurrency{
+				Type:      s.Concurrency.Type.Value,
+				HardLimit: s.Concurrency.HardLimit.Value,
+				SoftLimit: s.Concurrency.SoftLimit.Value,
+			}
+		}
+		services = append(services, service)
+	}
+	return services
+}
+
+func ServicesToTfServices(input []apiv1.Service) []TfService {
+	tfservices := make([]TfService, 0)
+	for _, s := range input {
+		var tfports []TfPort
+		for _, j := range s.Ports {
+			var handlers []types.String
+			for _, k := range j.Handlers {
+				handlers = append(handlers, types.String{Value: k})
+			}
+			tfport := TfPort{
+				Port:       types.Int64{Value: j.Port},
+				Handlers:   handlers,
+				ForceHttps: boolFromPointer(j.ForceHttps),
+			}
+			if j.TLSOptions != nil {
+				tfport.TLSOptions = &TfTLSOptions{
+					Alpn:     j.TLSOptions.Alpn,
+					Versions: j.TLSOptions.Versions,
+				}
+			}
+			if j.HTTPOptions != nil {
+				tfport.HTTPOptions = &TfHTTPOptions{
+					Compress: boolFromPointer(j.HTTPOptions.Compress),
+				}
+				if j.HTTPOptions.Response != nil && len(j.HTTPOptions.Response.Headers) > 0 {
+					tfport.HTTPOptions.ResponseHeaders = map[string]string{}
+					for k, v := range j.HTTPOptions.Response.Headers {
+						tfport.HTTPOptions.ResponseHeaders[k] = fmt.Sprint(v)
+					}
+				}
+			}
+			if j.ProxyProtoOptions != nil {
+				tfport.ProxyProtoOptions = &TfProxyProtoOptions{
+					Version: optionalString(j.ProxyProtoOptions.Version),
+				}
+			}
+			tfports = append(tfports, tfport)
+		}
+		tfservice := TfService{
+			Ports:        tfports,
+			Protocol:     types.String{Value: s.Protocol},
+			InternalPort: types.Int64{Value: s.InternalPort},
+			Autostop:     boolFromPointer(s.Autostop),
+			Autostart:    boolFromPointer(s.Autostart),
+		}
+		if s.Concurrency != nil {
+			tfservice.Concurrency = &TfConcurrency{
+				Type:      types.String{Value: s.Concurrency.Type},
+				HardLimit: optionalInt64(s.Concurrency.HardLimit),
+				SoftLimit: optionalInt64(s.Concurrency.SoftLimit),
+			}
+		}
+		tfservices = append(tfservices, tfservice)
+	}
+	return tfservices
+}
+
+func TfRestartToRestart(ctx context.Context, input types.Object) (*apiv1.MachineRestart, diag.Diagnostics) {
+	if input.Null || input.Unknown {
+		return nil, nil
+	}
+	var tfrestart TfRestart
+	diags := input.As(ctx, &tfrestart, types.ObjectAsOptions{})
+	if diags.HasError() {
+		return nil, diags
+	}
+	restart := &apiv1.MachineRestart{
+		Policy: tfrestart.Policy.Value,
+	}
+	if !tfrestart.MaxRetries.Unknown {
+		restart.MaxRetries = tfrestart.MaxRetries.Value
+	}
+	return restart, diags
+}
+
+func RestartToTfRestart(input apiv1.MachineRestart) types.Object {
+	return types.Object{
+		AttrTypes: restartAttrTypes,
+		Attrs: map[string]attr.Value{
+			"policy":      types.String{Value: input.Policy},
+			"max_retries": types.Int64{Value: input.MaxRetries},
+		},
+	}
+}
+
+func ImageRefToTfImageRef(input apiv1.ImageRef) types.Object {
+	return types.Object{
+		AttrTypes: imageRefAttrTypes,
+		Attrs: map[string]attr.Value{
+			"registry":   types.String{Value: input.Registry},
+			"repository": types.String{Value: input.Repository},
+			"tag":        types.String{Value: input.Tag},
+			"digest":     types.String{Value: input.Digest},
+			"labels":     utils.KVToTfMap(input.Labels, types.StringType),
+		},
+	}
+}
+
+func TfProcessesToProcesses(input []TfProcess) []apiv1.MachineProcess {
+	processes := make([]apiv1.MachineProcess, 0)
+	for _, p := range input {
+		processes = append(processes, apiv1.MachineProcess{
+			Name:       p.Name.Value,
+			Entrypoint: p.Entrypoint,
+			Cmd:        p.Cmd,
+			Exec:       p.Exec,
+			Env:        p.Env,
+			User:       p.User.Value,
+		})
+	}
+	return processes
+}
+
+func ProcessesToTfProcesses(input []apiv1.MachineProcess) []TfProcess {
+	tfprocesses := make([]TfProcess, 0)
+	for _, p := range input {
+		var env map[string]string
+		if len(p.Env) > 0 {
+			env = p.Env
+		}
+		tfprocesses = append(tfprocesses, TfProcess{
+			Name:       optionalString(p.Name),
+			Entrypoint: p.Entrypoint,
+			Cmd:        p.Cmd,
+			Exec:       p.Exec,
+			Env:        env,
+			User:       optionalString(p.User),
+		})
+	}
+	return tfprocesses
+}
+
+// fileSources counts how many of content, content_base64 and secret_name a file sets, values that aren't known yet
+// count as set
+func fileSources(f TfFile) int {
+	sources := 0
+	for _, source := range []types.String{f.Content, f.ContentBase64, f.SecretName} {
+		if !source.Null {
+			sources++
+		}
+	}
+	return sources
+}
+
+func fileSourcesError(f TfFile) error {
+	return errors.New(fmt.Sprintf("file %s must set exactly one of content, content_base64 or secret_name", f.GuestPath.Value))
+}
+
+func TfFilesToFiles(input []TfFile) ([]apiv1.MachineFile, error) {
+	var files []apiv1.MachineFile
+	for _, f := range input {
+		if fileSources(f) != 1 {
+			return nil, fileSourcesError(f)
+		}
+		file := apiv1.MachineFile{
+			GuestPath: f.GuestPath.Value,
+		}
+		if !f.Content.Null {
+			file.RawValue = base64.StdEncoding.EncodeToString([]byte(f.Content.Value))
+		}
+		if !f.ContentBase64.Null {
+			file.RawValue = f.ContentBase64.Value
+		}
+		if !f.SecretName.Null {
+			file.SecretName = f.SecretName.Value
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// FilesToTfFiles maps files returned by the api back to terraform. The api only knows about base64 content so the prior
+// value is used to decide whether it should be shown as content or content_base64.
+func FilesToTfFiles(input []apiv1.MachineFile, prior []TfFile) []TfFile {
+	priorByPath := map[string]TfFile{}
+	for _, f := range prior {
+		priorByPath[f.GuestPath.Value] = f
+	}
+
+	var tffiles []TfFile
+	if prior != nil && len(input) == 0 {
+		tffiles = []TfFile{}
+	}
+	for _, f := range input {
+		p, hasPrior := priorByPath[f.GuestPath]
+		tffile := TfFile{
+			GuestPath:     types.String{Value: f.GuestPath},
+			Content:       types.String{Null: true},
+			ContentBase64: types.String{Null: true},
+			SecretName:    optionalString(f.SecretName),
+		}
+		if f.RawValue != "" {
+			decoded, err := base64.StdEncoding.DecodeString(f.RawValue)
+			if hasPrior && !p.Content.Null && err == nil {
+				tffile.Content = types.String{Value: string(decoded)}
+			} else {
+				tffile.ContentBase64 = types.String{Value: f.RawValue}
+			}
+		} else if f.SecretName == "" && hasPrior {
+			// the api doesn't always echo file contents back
+			tffile.Content = p.Content
+			tffile.ContentBase64 = p.ContentBase64
+		}
+		tffiles = append(tffiles, tffile)
+	}
+	return tffiles
+}
+
+// PinnedImage returns the image to send to the api, with the digest appended when the image is pinned
+func PinnedImage(image types.String, pin types.Bool, digest types.String) string {
+	if !pin.Value || digest.Null || digest.Unknown || digest.Value == "" || strings.Contains(image.Value, "@") {
+		return image.Value
+	}
+	return image.Value + "@" + digest.Value
+}
+
+// UnpinImage splits the digest added by PinnedImage back off the image returned by the api. An image that was
+// configured with a digest keeps it, PinnedImage didn't add one.
+func UnpinImage(image string, configured types.String, pin types.Bool) (types.String, types.String) {
+	if !pin.Value {
+		return types.String{Value: image}, types.String{Null: true}
+	}
+	i := strings.Index(image, "@")
+	if i == -1 {
+		return types.String{Value: image}, types.String{Null: true}
+	}
+	if strings.Contains(configured.Value, "@") {
+		return types.String{Value: image}, types.String{Value: image[i+1:]}
+	}
+	return types.String{Value: image[:i]}, types.String{Value: image[i+1:]}
+}
+
+// MachineStateToDesiredState collapses the transitional states reported by the machines api into the
+// `started`/`stopped`/`suspended` values accepted by desired_state so that a machine on its way to a state doesn't
+// show as drift
+func MachineStateToDesiredState(state string) string {
+	switch state {
+	case "created", "starting", "started", "replacing":
+		return "started"
+	case "stopping", "stopped":
+		return "stopped"
+	case "suspending", "suspended":
+		return "suspended"
+	default:
+		return state
+	}
+}
+
+// runsOnce is whether the machine is a job that exits by itself, like a scheduled or auto destroyed machine. These don't
+// stay started, so applying them doesn't wait for them to start
+func runsOnce(restart apiv1.MachineRestart, autoDestroy bool, schedule string) bool {
+	return autoDestroy || schedule != "" || restart.Policy == "no"
+}
+
+// desiredStateRuns is whether a machine in the desired state is running and so can pass health checks
+func desiredStateRuns(desired string) bool {
+	return desired != "stopped" && desired != "suspended"
+}
+
+// reconcileCordon cordons or uncordons the machine to match the plan. When cordoned isn't set the machine is left
+// alone and keeps the prior value.
+func reconcileCordon(machineAPI *apiv1.MachineAPI, app string, id string, planned types.Bool, prior types.Bool) (types.Bool, error) {
+	if planned.Unknown || planned.Null {
+		return types.Bool{Value: prior.Value}, nil
+	}
+
+	var err error
+	if planned.Value {
+		err = machineAPI.CordonMachine(app, id)
+	} else if prior.Value {
+		err = machineAPI.UncordonMachine(app, id)
+	}
+	if err != nil {
+		return types.Bool{}, err
+	}
+	return types.Bool{Value: planned.Value}, nil
+}
+
+// CordonedToTfCordoned uses what the api reports when it reports it, not every api version does, and otherwise trusts
+// the prior value
+func CordonedToTfCordoned(cordoned *bool, prior types.Bool) types.Bool {
+	if cordoned != nil {
+		return types.Bool{Value: *cordoned}
+	}
+	return types.Bool{Value: prior.Value}
+}
+
+// machineConfigChanged is whether the plan changes anything that goes into the machine config. Computed attributes that
+// are left out of the config are unknown in any plan that changes something else, those are up to the api and don't
+// count as a change.
+func machineConfigChanged(plan tftypes.Value, state tftypes.Value, config tftypes.Value) (bool, error) {
+	var planned, prior, configured map[string]tftypes.Value
+	if err := plan.As(&planned); err != nil {
+		return false, err
+	}
+	if err := state.As(&prior); err != nil {
+		return false, err
+	}
+	if err := config.As(&configured); err != nil {
+		return false, err
+	}
+
+	for name, value := range planned {
+		if lifecycleAttributes[name] {
+			continue
+		}
+		if !value.IsKnown() && configured[name].IsNull() {
+			continue
+		}
+		if !value.Equal(prior[name]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// keepComputedState fills the attributes that are left out of the config and unknown in the plan with their values in
+// state
+func keepComputedState(plan tftypes.Value, state tftypes.Value, config tftypes.Value) (tftypes.Value, error) {
+	var planned, prior, configured map[string]tftypes.Value
+	if err := plan.As(&planned); err != nil {
+		return plan, err
+	}
+	if err := state.As(&prior); err != nil {
+		return plan, err
+	}
+	if err := config.As(&configured); err != nil {
+		return plan, err
+	}
+
+	for name, value := range planned {
+		if !value.IsKnown() && configured[name].IsNull() {
+			planned[name] = prior[name]
+		}
+	}
+	return tftypes.NewValue(plan.Type(), planned), nil
+}
+
+// reconcileDesiredState starts or stops the machine so that it ends up in the desired state and returns the
+// state it was left in
+func (mr flyMachineResource) reconcileDesiredState(ctx context.Context, machineAPI *apiv1.MachineAPI, app string, id string, desired string, timeout time.Duration) (string, error) {
+	var machine apiv1.MachineResponse
+	err := machineAPI.ReadMachine(app, id, &machine)
+	if err != nil {
+		return "", err
+	}
+
+	current := MachineStateToDesiredState(machine.State)
+	if desired == "" || desired == current {
+		return current, nil
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Moving machine %s from %s to %s", id, machine.State, desired))
+
+	switch desired {
+	case "started":
+		err = machineAPI.StartMachine(app, id)
+	case "stopped":
+		err = machineAPI.StopMachine(app, id, apiv1.MachineStopRequest{})
+	case "suspended":
+		err = machineAPI.SuspendMachine(app, id)
+	default:
+		err = errors.New("unknown desired state " + desired)
+	}
 	if err != nil {
-		return false, errors.New("can't connect to the api, is the tunnel open? :)")
+		return "", err
 	}
-	return true, nil
+
+	err = machineAPI.WaitForMachine(app, id, machine.InstanceID, desired, timeout)
+	if err != nil {
+		return "", err
+	}
+	return desired, nil
 }
 
-func TfServicesToServices(input []TfService) []apiv1.Service {
-	services := make([]apiv1.Service, 0)
-	for _, s := range input {
-		var ports []apiv1.Port
-		for _, j := range s.Ports {
-			var handlers []string
-			for _, k := range j.Handlers {
-				handlers = append(handlers, k.Value)
-			}
-			ports = append(ports, apiv1.Port{
-				Port:     j.Port.Value,
-				Handlers: handlers,
+func TfChecksToChecks(input map[string]TfCheck) map[string]apiv1.MachineCheck {
+	if len(input) == 0 {
+		return nil
+	}
+	checks := make(map[string]apiv1.MachineCheck)
+	for name, c := range input {
+		var headers []apiv1.MachineHTTPHeader
+		for k, v := range c.Headers {
+			headers = append(headers, apiv1.MachineHTTPHeader{
+				Name:   k,
+				Values: []string{v},
 			})
 		}
-		services = append(services, apiv1.Service{
-			Ports:        ports,
-			Protocol:     s.Protocol.Value,
-			InternalPort: s.InternalPort.Value,
-		})
+		checks[name] = apiv1.MachineCheck{
+			Type:        c.Type.Value,
+			Port:        c.Port.Value,
+			Interval:    c.Interval.Value,
+			Timeout:     c.Timeout.Value,
+			GracePeriod: c.GracePeriod.Value,
+			Method:      c.Method.Value,
+			Path:        c.Path.Value,
+			Protocol:    c.Protocol.Value,
+			Headers:     headers,
+		}
 	}
-	return services
+	return checks
 }
 
-func ServicesToTfServices(input []apiv1.Service) []TfService {
-	tfservices := make([]TfService, 0)
-	for _, s := range input {
-		var tfports []TfPort
-		for _, j := range s.Ports {
-			var handlers []types.String
-			for _, k := range j.Handlers {
-				handlers = append(handlers, types.String{Value: k})
+// ChecksToTfChecks maps checks returned by the api back to terraform. The api normalizes durations (`1m` comes back
+// as `1m0s`) so the prior value is kept whenever it describes the same duration.
+func ChecksToTfChecks(input map[string]apiv1.MachineCheck, prior map[string]TfCheck) map[string]TfCheck {
+	if len(input) == 0 {
+		if prior != nil {
+			return map[string]TfCheck{}
+		}
+		return nil
+	}
+	tfchecks := make(map[string]TfCheck)
+	for name, c := range input {
+		var headers map[string]string
+		for _, h := range c.Headers {
+			if headers == nil {
+				headers = map[string]string{}
 			}
-			tfports = append(tfports, TfPort{
-				Port:     types.Int64{Value: j.Port},
-				Handlers: handlers,
-			})
+			headers[h.Name] = strings.Join(h.Values, ", ")
+		}
+		p := prior[name]
+		tfchecks[name] = TfCheck{
+			Type:        types.String{Value: c.Type},
+			Port:        types.Int64{Value: c.Port},
+			Interval:    durationToTfString(c.Interval, p.Interval),
+			Timeout:     durationToTfString(c.Timeout, p.Timeout),
+			GracePeriod: durationToTfString(c.GracePeriod, p.GracePeriod),
+			Method:      optionalString(c.Method),
+			Path:        optionalString(c.Path),
+			Protocol:    optionalString(c.Protocol),
+			Headers:     headers,
 		}
-		tfservices = append(tfservices, TfService{
-			Ports:        tfports,
-			Protocol:     types.String{Value: s.Protocol},
-			InternalPort: types.Int64{Value: s.InternalPort},
-		})
 	}
-	return tfservices
+	return tfchecks
+}
+
+func durationToTfString(value string, prior types.String) types.String {
+	if value == "" {
+		return types.String{Null: true}
+	}
+	if !prior.Null && !prior.Unknown {
+		priorDuration, priorErr := time.ParseDuration(prior.Value)
+		duration, err := time.ParseDuration(value)
+		if priorErr == nil && err == nil && priorDuration == duration {
+			return prior
+		}
+	}
+	return types.String{Value: value}
+}
+
+func optionalString(value string) types.String {
+	if value == "" {
+		return types.String{Null: true}
+	}
+	return types.String{Value: value}
+}
+
+func optionalInt64(value int64) types.Int64 {
+	if value == 0 {
+		return types.Int64{Null: true}
+	}
+	return types.Int64{Value: value}
+}
+
+// boolPointer only sends booleans that were set so the api can apply its own defaults
+func boolPointer(value types.Bool) *bool {
+	if value.Null || value.Unknown {
+		return nil
+	}
+	return &value.Value
+}
+
+func boolFromPointer(value *bool) types.Bool {
+	if value == nil {
+		return types.Bool{Value: false}
+	}
+	return types.Bool{Value: *value}
+}
+
+// destroyOptions is how the machine described by data should be torn down
+func destroyOptions(data flyMachineResourceData, timeout time.Duration) apiv1.MachineDestroyOptions {
+	opts := apiv1.MachineDestroyOptions{
+		Signal:  data.StopSignal.Value,
+		Force:   data.ForceDestroy.Value,
+		Timeout: timeout,
+	}
+	// Invalid durations are rejected by the validator
+	if stopTimeout, err := time.ParseDuration(data.StopTimeout.Value); err == nil {
+		opts.StopTimeout = stopTimeout
+	}
+	return opts
+}
+
+// addMachineError reports a failed machine request, calling out leases held by someone else since those need the
+// user to wait or clear the lease rather than fix their config
+func addMachineError(diags *diag.Diagnostics, summary string, err error) {
+	var leaseErr *apiv1.LeaseHeldError
+	if errors.As(err, &leaseErr) {
+		diags.AddError("Machine is leased by another owner", fmt.Sprintf("%s. Wait for the other operation to finish or clear the lease with `fly machine leases clear %s --app %s`", err, leaseErr.ID, leaseErr.App))
+		return
+	}
+	diags.AddError(summary, err.Error())
 }
 
 func (mr flyMachineResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -299,8 +1292,10 @@ func (mr flyMachineResource) Create(ctx context.Context, req resource.CreateRequ
 		Name:   data.Name.Value,
 		Region: data.Region.Value,
 		Config: apiv1.MachineConfig{
-			Image:    data.Image.Value,
-			Services: services,
+			Image:     PinnedImage(data.Image, data.PinImageDigest, data.ImageDigest),
+			Services:  services,
+			Checks:    TfChecksToChecks(data.Checks),
+			Processes: TfProcessesToProcesses(data.Processes),
 			Init: apiv1.InitConfig{
 				Cmd:        data.Cmd,
 				Entrypoint: data.Entrypoint,
@@ -324,6 +1319,30 @@ func (mr flyMachineResource) Create(ctx context.Context, req resource.CreateRequ
 		data.Env.ElementsAs(context.Background(), &env, false)
 		createReq.Config.Env = env
 	}
+	if !data.Metadata.Unknown {
+		var metadata map[string]string
+		data.Metadata.ElementsAs(context.Background(), &metadata, false)
+		createReq.Config.Metadata = metadata
+	}
+	if !data.AutoDestroy.Unknown {
+		createReq.Config.AutoDestroy = data.AutoDestroy.Value
+	}
+	createReq.Config.Schedule = data.Schedule.Value
+	createReq.Config.Standbys = data.Standbys
+
+	restart, diags := TfRestartToRestart(ctx, data.Restart)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	createReq.Config.Restart = restart
+	files, err := TfFilesToFiles(data.Files)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("files"), "Invalid file", err.Error())
+		return
+	}
+	createReq.Config.Files = files
+
 	if len(data.Mounts) > 0 {
 		var mounts []apiv1.MachineMount
 		for _, m := range data.Mounts {
@@ -337,6 +1356,22 @@ func (mr flyMachineResource) Create(ctx context.Context, req resource.CreateRequ
 		createReq.Config.Mounts = mounts
 	}
 
+	desiredState := ""
+	if !data.DesiredState.Unknown && !data.DesiredState.Null {
+		desiredState = data.DesiredState.Value
+	}
+	plannedChecks := data.Checks
+	waitForChecks := data.WaitForChecks
+	updateStrategy := data.UpdateStrategy
+	pinImageDigest := data.PinImageDigest
+	plannedFiles := data.Files
+	plannedTimeouts := data.Timeouts
+	plannedCordoned := data.Cordoned
+	stopSignal := data.StopSignal
+	stopTimeout := data.StopTimeout
+	forceDestroy := data.ForceDestroy
+	timeout := createTimeout(data.Timeouts)
+
 	machineAPI := apiv1.NewMachineAPI(mr.provider.httpClient, mr.provider.httpEndpoint)
 
 	var newMachine apiv1.MachineResponse
@@ -356,13 +1391,29 @@ func (mr flyMachineResource) Create(ctx context.Context, req resource.CreateRequ
 		tfservices = nil
 	}
 
+	tfprocesses := ProcessesToTfProcesses(newMachine.Config.Processes)
+
+	if data.Processes == nil && len(tfprocesses) == 0 {
+		tfprocesses = nil
+	}
+
+	standbys := newMachine.Config.Standbys
+
+	if data.Standbys == nil && len(standbys) == 0 {
+		standbys = nil
+	} else if standbys == nil {
+		standbys = []string{}
+	}
+
+	image, imageDigest := UnpinImage(newMachine.Config.Image, data.Image, pinImageDigest)
+
 	data = flyMachineResourceData{
 		Name:       types.String{Value: newMachine.Name},
 		Region:     types.String{Value: newMachine.Region},
 		Id:         types.String{Value: newMachine.ID},
 		App:        types.String{Value: data.App.Value},
 		PrivateIP:  types.String{Value: newMachine.PrivateIP},
-		Image:      types.String{Value: newMachine.Config.Image},
+		Image:      image,
 		Cpus:       types.Int64{Value: int64(newMachine.Config.Guest.Cpus)},
 		MemoryMb:   types.Int64{Value: int64(newMachine.Config.Guest.MemoryMb)},
 		CpuType:    types.String{Value: newMachine.Config.Guest.CPUKind},
@@ -371,6 +1422,31 @@ func (mr flyMachineResource) Create(ctx context.Context, req resource.CreateRequ
 		Exec:       newMachine.Config.Init.Exec,
 		Env:        env,
 		Services:   tfservices,
+
+		Checks:        ChecksToTfChecks(newMachine.Config.Checks, plannedChecks),
+		WaitForChecks: waitForChecks,
+
+		UpdateStrategy: updateStrategy,
+
+		StopSignal:   stopSignal,
+		StopTimeout:  stopTimeout,
+		ForceDestroy: forceDestroy,
+
+		Timeouts: plannedTimeouts,
+
+		Restart:     RestartToTfRestart(newMachine.Config.Restart),
+		AutoDestroy: types.Bool{Value: newMachine.Config.AutoDestroy},
+		Schedule:    optionalString(newMachine.Config.Schedule),
+		Standbys:    standbys,
+
+		Metadata: utils.KVToTfMap(newMachine.Config.Metadata, types.StringType),
+		ImageRef: ImageRefToTfImageRef(newMachine.ImageRef),
+
+		PinImageDigest: pinImageDigest,
+		ImageDigest:    imageDigest,
+
+		Files:     FilesToTfFiles(newMachine.Config.Files, plannedFiles),
+		Processes: tfprocesses,
 	}
 
 	if len(newMachine.Config.Mounts) > 0 {
@@ -386,10 +1462,46 @@ func (mr flyMachineResource) Create(ctx context.Context, req resource.CreateRequ
 		data.Mounts = tfmounts
 	}
 
-	err = machineAPI.WaitForMachine(data.App.Value, data.Id.Value, newMachine.InstanceID)
+	// A machine that doesn't come up is still saved to state, the error taints it so the next apply replaces it
+	data.DesiredState = types.String{Value: MachineStateToDesiredState(newMachine.State)}
+
+	deadline := time.Now().Add(timeout)
+	oneShot := runsOnce(newMachine.Config.Restart, newMachine.Config.AutoDestroy, newMachine.Config.Schedule)
+
+	if !oneShot {
+		err = machineAPI.WaitForMachine(data.App.Value, data.Id.Value, newMachine.InstanceID, "started", timeout)
+		if err != nil {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			resp.Diagnostics.AddError("Machine failed to start", err.Error())
+			return
+		}
+
+		if waitForChecks.Value && desiredStateRuns(desiredState) {
+			err = machineAPI.WaitForHealthChecks(data.App.Value, data.Id.Value, time.Until(deadline))
+			if err != nil {
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+				resp.Diagnostics.AddError("Machine health checks failed", err.Error())
+				return
+			}
+		}
+	}
+
+	// A job that was left to run may already have exited or been destroyed, so it's only moved when asked to
+	if !oneShot || desiredState != "" {
+		state, err := mr.reconcileDesiredState(ctx, machineAPI, data.App.Value, data.Id.Value, desiredState, time.Until(deadline))
+		if err != nil {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			resp.Diagnostics.AddError("Failed to move machine to desired state", err.Error())
+			return
+		}
+		data.DesiredState = types.String{Value: state}
+	}
+
+	data.Cordoned, err = reconcileCordon(machineAPI, data.App.Value, data.Id.Value, plannedCordoned, types.Bool{Value: false})
 	if err != nil {
-		//FIXME(?): For now we just assume that the orchestrator is in fact going to faithfully execute our request
-		tflog.Info(ctx, "Waiting errored")
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		resp.Diagnostics.AddError("Failed to cordon machine", err.Error())
+		return
 	}
 
 	diags = resp.State.Set(ctx, &data)
@@ -414,9 +1526,19 @@ func (mr flyMachineResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	var machine apiv1.MachineResponse
 
-	_, err = machineAPI.ReadMachine(data.App.Value, data.Id.Value, &machine)
+	err = machineAPI.ReadMachine(data.App.Value, data.Id.Value, &machine)
+	if apiv1.IsNotFound(err) {
+		tflog.Info(ctx, fmt.Sprintf("Machine %s not found, removing it from state", data.Id.Value))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create machine", err.Error())
+		resp.Diagnostics.AddError("Failed to read machine", err.Error())
+		return
+	}
+	if machine.State == "destroying" || machine.State == "destroyed" {
+		tflog.Info(ctx, fmt.Sprintf("Machine %s is %s, removing it from state", data.Id.Value, machine.State))
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
@@ -428,13 +1550,29 @@ func (mr flyMachineResource) Read(ctx context.Context, req resource.ReadRequest,
 		tfservices = nil
 	}
 
+	tfprocesses := ProcessesToTfProcesses(machine.Config.Processes)
+
+	if data.Processes == nil && len(tfprocesses) == 0 {
+		tfprocesses = nil
+	}
+
+	standbys := machine.Config.Standbys
+
+	if data.Standbys == nil && len(standbys) == 0 {
+		standbys = nil
+	} else if standbys == nil {
+		standbys = []string{}
+	}
+
+	image, imageDigest := UnpinImage(machine.Config.Image, data.Image, data.PinImageDigest)
+
 	data = flyMachineResourceData{
 		Name:       types.String{Value: machine.Name},
 		Id:         types.String{Value: machine.ID},
 		Region:     types.String{Value: machine.Region},
 		App:        types.String{Value: data.App.Value},
 		PrivateIP:  types.String{Value: machine.PrivateIP},
-		Image:      types.String{Value: machine.Config.Image},
+		Image:      image,
 		Cpus:       types.Int64{Value: int64(machine.Config.Guest.Cpus)},
 		MemoryMb:   types.Int64{Value: int64(machine.Config.Guest.MemoryMb)},
 		CpuType:    types.String{Value: machine.Config.Guest.CPUKind},
@@ -443,6 +1581,34 @@ func (mr flyMachineResource) Read(ctx context.Context, req resource.ReadRequest,
 		Exec:       machine.Config.Init.Exec,
 		Env:        env,
 		Services:   tfservices,
+
+		DesiredState: types.String{Value: MachineStateToDesiredState(machine.State)},
+		Cordoned:     CordonedToTfCordoned(machine.Cordoned, data.Cordoned),
+
+		Checks:        ChecksToTfChecks(machine.Config.Checks, data.Checks),
+		WaitForChecks: data.WaitForChecks,
+
+		UpdateStrategy: data.UpdateStrategy,
+
+		StopSignal:   data.StopSignal,
+		StopTimeout:  data.StopTimeout,
+		ForceDestroy: data.ForceDestroy,
+
+		Timeouts: data.Timeouts,
+
+		Restart:     RestartToTfRestart(machine.Config.Restart),
+		AutoDestroy: types.Bool{Value: machine.Config.AutoDestroy},
+		Schedule:    optionalString(machine.Config.Schedule),
+		Standbys:    standbys,
+
+		Metadata: utils.KVToTfMap(machine.Config.Metadata, types.StringType),
+		ImageRef: ImageRefToTfImageRef(machine.ImageRef),
+
+		PinImageDigest: data.PinImageDigest,
+		ImageDigest:    imageDigest,
+
+		Files:     FilesToTfFiles(machine.Config.Files, data.Files),
+		Processes: tfprocesses,
 	}
 
 	if len(machine.Config.Mounts) > 0 {
@@ -465,6 +1631,50 @@ func (mr flyMachineResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 }
 
+// staleMachineError is a blue-green update whose replacement is healthy but whose old machine couldn't be destroyed
+type staleMachineError struct {
+	App string
+	ID  string
+	Err error
+}
+
+func (e *staleMachineError) Error() string {
+	return fmt.Sprintf("machine %s was replaced but could not be destroyed: %s. Destroy it with `fly machine destroy %s --app %s`", e.ID, e.Err, e.ID, e.App)
+}
+
+// replaceMachine creates a machine from req, waits for it to come up and then destroys oldID. It returns whether res
+// is a machine terraform has to keep track of, which is the case unless the replacement was destroyed again. A
+// replacement that is kept while the old machine isn't destroyed comes with a *staleMachineError.
+func (mr flyMachineResource) replaceMachine(ctx context.Context, machineAPI *apiv1.MachineAPI, req apiv1.MachineCreateOrUpdateRequest, app string, oldID string, waitForChecks bool, timeout time.Duration, destroy apiv1.MachineDestroyOptions, res *apiv1.MachineResponse) (bool, error) {
+	deadline := time.Now().Add(timeout)
+
+	err := machineAPI.CreateMachine(req, app, res)
+	if err != nil {
+		return false, err
+	}
+	tflog.Info(ctx, fmt.Sprintf("Created replacement machine %s for %s", res.ID, oldID))
+
+	if !runsOnce(res.Config.Restart, res.Config.AutoDestroy, res.Config.Schedule) {
+		err = machineAPI.WaitForMachine(app, res.ID, res.InstanceID, "started", time.Until(deadline))
+		if err == nil && waitForChecks {
+			err = machineAPI.WaitForHealthChecks(app, res.ID, time.Until(deadline))
+		}
+	}
+	if err != nil {
+		if deleteErr := machineAPI.DeleteMachine(app, res.ID, destroy); deleteErr != nil {
+			return true, errors.New(fmt.Sprintf("replacement machine %s did not become healthy: %s, and could not be destroyed: %s. The replaced machine %s is still running", res.ID, err, deleteErr, oldID))
+		}
+		return false, errors.New(fmt.Sprintf("replacement machine %s did not become healthy, kept %s: %s", res.ID, oldID, err))
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Destroying replaced machine %s", oldID))
+	err = machineAPI.DeleteMachine(app, oldID, destroy)
+	if err != nil {
+		return true, &staleMachineError{App: app, ID: oldID, Err: err}
+	}
+	return true, nil
+}
+
 func (mr flyMachineResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	_, err := mr.ValidateOpenTunnel()
 	if err != nil {
@@ -497,14 +1707,26 @@ func (mr flyMachineResource) Update(ctx context.Context, req resource.UpdateRequ
 		resp.Diagnostics.AddError("Can't mutate region of existing machine", "Can't switch region "+state.Name.Value+" to "+plan.Name.Value)
 	}
 
+	configChanged, err := machineConfigChanged(req.Plan.Raw, req.State.Raw, req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compare machine config", err.Error())
+		return
+	}
+	if !configChanged {
+		mr.updateLifecycle(ctx, plan, state, resp)
+		return
+	}
+
 	services := TfServicesToServices(plan.Services)
 
 	updateReq := apiv1.MachineCreateOrUpdateRequest{
 		Name:   plan.Name.Value,
 		Region: state.Region.Value,
 		Config: apiv1.MachineConfig{
-			Image:    plan.Image.Value,
-			Services: services,
+			Image:     PinnedImage(plan.Image, plan.PinImageDigest, plan.ImageDigest),
+			Services:  services,
+			Checks:    TfChecksToChecks(plan.Checks),
+			Processes: TfProcessesToProcesses(plan.Processes),
 			Init: apiv1.InitConfig{
 				Cmd:        plan.Cmd,
 				Entrypoint: plan.Entrypoint,
@@ -532,6 +1754,36 @@ func (mr flyMachineResource) Update(ctx context.Context, req resource.UpdateRequ
 	} else if !state.Env.Unknown {
 		updateReq.Config.Env = map[string]string{}
 	}
+	if plan.Metadata.Null {
+		updateReq.Config.Metadata = map[string]string{}
+	} else if !plan.Metadata.Unknown {
+		var metadata map[string]string
+		plan.Metadata.ElementsAs(context.Background(), &metadata, false)
+		updateReq.Config.Metadata = metadata
+	} else if !state.Metadata.Null {
+		var metadata map[string]string
+		state.Metadata.ElementsAs(context.Background(), &metadata, false)
+		updateReq.Config.Metadata = metadata
+	}
+	if !plan.AutoDestroy.Unknown {
+		updateReq.Config.AutoDestroy = plan.AutoDestroy.Value
+	}
+	updateReq.Config.Schedule = plan.Schedule.Value
+	updateReq.Config.Standbys = plan.Standbys
+
+	restart, diags := TfRestartToRestart(ctx, plan.Restart)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	updateReq.Config.Restart = restart
+
+	files, err := TfFilesToFiles(plan.Files)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("files"), "Invalid file", err.Error())
+		return
+	}
+	updateReq.Config.Files = files
 
 	if len(plan.Mounts) > 0 {
 		var mounts []apiv1.MachineMount
@@ -550,23 +1802,54 @@ func (mr flyMachineResource) Update(ctx context.Context, req resource.UpdateRequ
 
 	var updatedMachine apiv1.MachineResponse
 
-	err = machineApi.UpdateMachine(updateReq, state.App.Value, state.Id.Value, &updatedMachine)
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to update machine", err.Error())
+	timeout := updateTimeout(plan.Timeouts)
+	deadline := time.Now().Add(timeout)
+	priorCordoned := state.Cordoned
+
+	kept := true
+	if plan.UpdateStrategy.Value == updateStrategyBlueGreen {
+		// The replacement exists alongside the old machine, so it can't take its name and gets a generated one
+		updateReq.Name = ""
+		// The old machine is only destroyed once the replacement is proven healthy, whatever wait_for_checks says
+		waitForChecks := desiredStateRuns(plan.DesiredState.Value)
+		kept, err = mr.replaceMachine(ctx, machineApi, updateReq, state.App.Value, state.Id.Value, waitForChecks, timeout, destroyOptions(plan, timeout), &updatedMachine)
+	} else {
+		err = machineApi.UpdateMachine(updateReq, state.App.Value, state.Id.Value, &updatedMachine)
+		kept = err == nil
+	}
+	if err != nil && !kept {
+		addMachineError(&resp.Diagnostics, "Failed to update machine", err)
 		return
 	}
+	updateErr := err
 
 	env := utils.KVToTfMap(updatedMachine.Config.Env, types.StringType)
 
 	tfservices := ServicesToTfServices(updatedMachine.Config.Services)
 
+	tfprocesses := ProcessesToTfProcesses(updatedMachine.Config.Processes)
+
+	if plan.Processes == nil && len(tfprocesses) == 0 {
+		tfprocesses = nil
+	}
+
+	standbys := updatedMachine.Config.Standbys
+
+	if plan.Standbys == nil && len(standbys) == 0 {
+		standbys = nil
+	} else if standbys == nil {
+		standbys = []string{}
+	}
+
+	image, imageDigest := UnpinImage(updatedMachine.Config.Image, plan.Image, plan.PinImageDigest)
+
 	state = flyMachineResourceData{
 		Name:       types.String{Value: updatedMachine.Name},
 		Region:     types.String{Value: updatedMachine.Region},
 		Id:         types.String{Value: updatedMachine.ID},
 		App:        types.String{Value: state.App.Value},
 		PrivateIP:  types.String{Value: updatedMachine.PrivateIP},
-		Image:      types.String{Value: updatedMachine.Config.Image},
+		Image:      image,
 		Cpus:       types.Int64{Value: int64(updatedMachine.Config.Guest.Cpus)},
 		MemoryMb:   types.Int64{Value: int64(updatedMachine.Config.Guest.MemoryMb)},
 		CpuType:    types.String{Value: updatedMachine.Config.Guest.CPUKind},
@@ -575,6 +1858,31 @@ func (mr flyMachineResource) Update(ctx context.Context, req resource.UpdateRequ
 		Exec:       updatedMachine.Config.Init.Exec,
 		Env:        env,
 		Services:   tfservices,
+
+		Checks:        ChecksToTfChecks(updatedMachine.Config.Checks, plan.Checks),
+		WaitForChecks: plan.WaitForChecks,
+
+		UpdateStrategy: plan.UpdateStrategy,
+
+		StopSignal:   plan.StopSignal,
+		StopTimeout:  plan.StopTimeout,
+		ForceDestroy: plan.ForceDestroy,
+
+		Timeouts: plan.Timeouts,
+
+		Restart:     RestartToTfRestart(updatedMachine.Config.Restart),
+		AutoDestroy: types.Bool{Value: updatedMachine.Config.AutoDestroy},
+		Schedule:    optionalString(updatedMachine.Config.Schedule),
+		Standbys:    standbys,
+
+		Metadata: utils.KVToTfMap(updatedMachine.Config.Metadata, types.StringType),
+		ImageRef: ImageRefToTfImageRef(updatedMachine.ImageRef),
+
+		PinImageDigest: plan.PinImageDigest,
+		ImageDigest:    imageDigest,
+
+		Files:     FilesToTfFiles(updatedMachine.Config.Files, plan.Files),
+		Processes: tfprocesses,
 	}
 
 	if len(updatedMachine.Config.Mounts) > 0 {
@@ -590,9 +1898,65 @@ func (mr flyMachineResource) Update(ctx context.Context, req resource.UpdateRequ
 		state.Mounts = tfmounts
 	}
 
-	err = machineApi.WaitForMachine(state.App.Value, state.Id.Value, updatedMachine.InstanceID)
+	// The update has been applied even if the machine doesn't come back up, so state is saved before failing
+	state.DesiredState = types.String{Value: MachineStateToDesiredState(updatedMachine.State)}
+
+	// A replacement that is kept despite an error is tracked from here on, only a stale old machine lets the update
+	// carry on
+	if updateErr != nil {
+		var stale *staleMachineError
+		if !errors.As(updateErr, &stale) {
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			addMachineError(&resp.Diagnostics, "Failed to update machine", updateErr)
+			return
+		}
+		resp.Diagnostics.AddWarning("Replaced machine was not destroyed", stale.Error())
+	}
+
+	desiredState := ""
+	if !plan.DesiredState.Unknown && !plan.DesiredState.Null {
+		desiredState = plan.DesiredState.Value
+	}
+	oneShot := runsOnce(updatedMachine.Config.Restart, updatedMachine.Config.AutoDestroy, updatedMachine.Config.Schedule)
+
+	if !oneShot {
+		err = machineApi.WaitForMachine(state.App.Value, state.Id.Value, updatedMachine.InstanceID, "started", time.Until(deadline))
+		if err != nil {
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			resp.Diagnostics.AddError("Machine failed to start", err.Error())
+			return
+		}
+
+		if plan.WaitForChecks.Value && desiredStateRuns(desiredState) {
+			err = machineApi.WaitForHealthChecks(state.App.Value, state.Id.Value, time.Until(deadline))
+			if err != nil {
+				resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+				resp.Diagnostics.AddError("Machine health checks failed", err.Error())
+				return
+			}
+		}
+	}
+
+	// A job that was left to run may already have exited or been destroyed, so it's only moved when asked to
+	if !oneShot || desiredState != "" {
+		machineState, err := mr.reconcileDesiredState(ctx, machineApi, state.App.Value, state.Id.Value, desiredState, time.Until(deadline))
+		if err != nil {
+			resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			resp.Diagnostics.AddError("Failed to move machine to desired state", err.Error())
+			return
+		}
+		state.DesiredState = types.String{Value: machineState}
+	}
+
+	// A replacement machine starts out uncordoned no matter what the old one was
+	if plan.UpdateStrategy.Value == updateStrategyBlueGreen {
+		priorCordoned = types.Bool{Value: false}
+	}
+	state.Cordoned, err = reconcileCordon(machineApi, state.App.Value, state.Id.Value, plan.Cordoned, priorCordoned)
 	if err != nil {
-		tflog.Info(ctx, "Waiting errored")
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		resp.Diagnostics.AddError("Failed to cordon machine", err.Error())
+		return
 	}
 
 	resp.State.Set(ctx, state)
@@ -601,6 +1965,155 @@ func (mr flyMachineResource) Update(ctx context.Context, req resource.UpdateRequ
 	}
 }
 
+// updateLifecycle applies a plan that leaves the machine config as it is. The machine isn't restarted, it's only
+// started, stopped or suspended when its desired state changed and cordoned or uncordoned. Teardown settings, update
+// settings and timeouts are taken from the plan without calling the api.
+func (mr flyMachineResource) updateLifecycle(ctx context.Context, plan flyMachineResourceData, state flyMachineResourceData, resp *resource.UpdateResponse) {
+	machineApi := apiv1.NewMachineAPI(mr.provider.httpClient, mr.provider.httpEndpoint)
+
+	state.StopSignal = plan.StopSignal
+	state.StopTimeout = plan.StopTimeout
+	state.ForceDestroy = plan.ForceDestroy
+	state.Timeouts = plan.Timeouts
+	state.WaitForChecks = plan.WaitForChecks
+	state.UpdateStrategy = plan.UpdateStrategy
+
+	if !plan.DesiredState.Unknown && !plan.DesiredState.Null && plan.DesiredState.Value != state.DesiredState.Value {
+		machineState, err := mr.reconcileDesiredState(ctx, machineApi, state.App.Value, state.Id.Value, plan.DesiredState.Value, updateTimeout(plan.Timeouts))
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to move machine to desired state", err.Error())
+			return
+		}
+		state.DesiredState = types.String{Value: machineState}
+	}
+
+	var err error
+	state.Cordoned, err = reconcileCordon(machineApi, state.App.Value, state.Id.Value, plan.Cordoned, state.Cordoned)
+	if err != nil {
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		resp.Diagnostics.AddError("Failed to cordon machine", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// ValidateConfig checks that every file has exactly one source and that blue-green updates can name the replacement
+// machine, so a bad config fails the plan rather than the apply
+func (mr flyMachineResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	mr.validateFiles(ctx, req, resp)
+	mr.validateUpdateStrategy(ctx, req, resp)
+}
+
+// validateUpdateStrategy rejects blue-green updates of a machine with a fixed name. Machine names are unique within an
+// app and the replacement is created while the old machine still exists, so it needs a generated name.
+func (mr flyMachineResource) validateUpdateStrategy(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var strategy types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("update_strategy"), &strategy)...)
+	var name types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name"), &name)...)
+	if resp.Diagnostics.HasError() || strategy.Value != updateStrategyBlueGreen || name.Null {
+		return
+	}
+	resp.Diagnostics.AddAttributeError(path.Root("name"), "Unsupported update strategy",
+		"blue-green updates can't be used with a fixed name, machine names are unique within an app and the replacement "+
+			"is created while the old machine still exists. Leave name unset to have one generated")
+}
+
+func (mr flyMachineResource) validateFiles(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var files types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("files"), &files)...)
+	if resp.Diagnostics.HasError() || files.Null || files.Unknown {
+		return
+	}
+
+	for i, elem := range files.Elems {
+		if elem.IsUnknown() {
+			continue
+		}
+		var file TfFile
+		diags := tfsdk.ValueAs(ctx, elem, &file)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+		if fileSources(file) != 1 {
+			resp.Diagnostics.AddAttributeError(path.Root("files").AtListIndex(i), "Invalid file", fileSourcesError(file).Error())
+		}
+	}
+}
+
+// ModifyPlan resolves the digest of pinned images so that a tag pointing somewhere new plans an update, and marks
+// the id as changing when an update will replace the machine. A plan that leaves the machine config as it is keeps
+// the computed attributes from state, since the machine isn't updated.
+func (mr flyMachineResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	mr.modifyPlanImageDigest(ctx, req, resp)
+	if resp.Diagnostics.HasError() || req.State.Raw.IsNull() {
+		return
+	}
+
+	configChanged, err := machineConfigChanged(resp.Plan.Raw, req.State.Raw, req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compare machine config", err.Error())
+		return
+	}
+	if !configChanged {
+		resp.Plan.Raw, err = keepComputedState(resp.Plan.Raw, req.State.Raw, req.Config.Raw)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to plan machine update", err.Error())
+		}
+		return
+	}
+
+	var strategy types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("update_strategy"), &strategy)...)
+	if resp.Diagnostics.HasError() || strategy.Value != updateStrategyBlueGreen {
+		return
+	}
+
+	var mounts []TfMachineMount
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("mounts"), &mounts)...)
+	if len(mounts) > 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("update_strategy"), "Unsupported update strategy", "blue-green updates can't be used with mounts, a volume can only be attached to one machine")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.String{Unknown: true})...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("privateip"), types.String{Unknown: true})...)
+}
+
+func (mr flyMachineResource) modifyPlanImageDigest(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var pin types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("pin_image_digest"), &pin)...)
+	var image types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("image"), &image)...)
+	if resp.Diagnostics.HasError() || !pin.Value || image.Unknown {
+		return
+	}
+
+	digest, err := registry.NewResolver(mr.provider.token).ResolveDigest(ctx, image.Value)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("image"), "Could not resolve image digest", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("image_digest"), digest)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var stateDigest types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("image_digest"), &stateDigest)...)
+	if stateDigest.Value != digest {
+		tflog.Info(ctx, fmt.Sprintf("%s now resolves to %s, was %s", image.Value, digest, stateDigest.Value))
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("image_ref"), types.Object{Unknown: true, AttrTypes: imageRefAttrTypes})...)
+	}
+}
+
 func (mr flyMachineResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data flyMachineResourceData
 
@@ -614,10 +2127,10 @@ func (mr flyMachineResource) Delete(ctx context.Context, req resource.DeleteRequ
 
 	machineApi := apiv1.NewMachineAPI(mr.provider.httpClient, mr.provider.httpEndpoint)
 
-	err = machineApi.DeleteMachine(data.App.Value, data.Id.Value, 50)
+	err = machineApi.DeleteMachine(data.App.Value, data.Id.Value, destroyOptions(data, deleteTimeout(data.Timeouts)))
 
 	if err != nil {
-		resp.Diagnostics.AddError("Machine delete failed", err.Error())
+		addMachineError(&resp.Diagnostics, "Machine delete failed", err)
 		return
 	}
 
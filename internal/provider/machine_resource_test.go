@@ -2,10 +2,14 @@ package provider
 
 import (
 	"fmt"
+	"github.com/fly-apps/terraform-provider-fly/pkg/apiv1"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"os"
+	"regexp"
 	"testing"
+	"time"
 )
 
 func TestAccFlyMachineBase(t *testing.T) {
@@ -367,3 +371,1098 @@ resource "fly_machine" "testMachine" {
 }
 `, app)
 }
+
+func TestAccFlyMachineDesiredState(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	var instanceID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceDesiredStateConfig(rName, "stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "desired_state", "stopped"),
+					testAccCheckMachineInstance(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+			{
+				Config: testFlyMachineResourceDesiredStateConfig(rName, "started"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "desired_state", "started"),
+					testAccCheckMachineNotUpdated(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+			{
+				Config: testFlyMachineResourceDesiredStateConfig(rName, "stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "desired_state", "stopped"),
+					testAccCheckMachineNotUpdated(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceDesiredStateConfig(name string, state string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+	desired_state = "%s"
+}
+`, app, name, state)
+}
+
+func TestAccFlyMachineChecks(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceChecksConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "checks.httpget.type", "http"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "checks.httpget.interval", "1m"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "checks.tcp.port", "80"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceChecksConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    wait_for_checks = true
+    checks = {
+      httpget = {
+        type     = "http"
+        port     = 80
+        interval = "1m"
+        timeout  = "10s"
+        method   = "GET"
+        path     = "/"
+        headers = {
+          Host = "example.com"
+        }
+      }
+      tcp = {
+        type = "tcp"
+        port = 80
+      }
+    }
+}
+`, app, name)
+}
+
+func TestAccFlyMachineServiceOptions(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceServiceOptionsConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "services.0.concurrency.hard_limit", "25"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "services.0.ports.0.tls_options.alpn.0", "h2"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "services.0.ports.1.force_https", "true"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "services.1.ports.0.proxy_proto_options.version", "v2"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceServiceOptionsConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    services = [
+      {
+        ports = [
+          {
+            port     = 443
+            handlers = ["tls", "http"]
+            tls_options = {
+              alpn     = ["h2", "http/1.1"]
+              versions = ["TLSv1.2", "TLSv1.3"]
+            }
+            http_options = {
+              compress = true
+            }
+          },
+          {
+            port        = 80
+            handlers    = ["http"]
+            force_https = true
+          }
+        ]
+        protocol      = "tcp"
+        internal_port = 80
+        autostop      = true
+        autostart     = true
+        concurrency = {
+          type       = "connections"
+          soft_limit = 20
+          hard_limit = 25
+        }
+      },
+      {
+        ports = [
+          {
+            port     = 5432
+            handlers = ["proxy_proto"]
+            proxy_proto_options = {
+              version = "v2"
+            }
+          }
+        ]
+        protocol      = "tcp"
+        internal_port = 5432
+      }
+    ]
+}
+`, app, name)
+}
+
+func TestAccFlyMachineRestartPolicy(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceRestartPolicyConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "restart.policy", "on-failure"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "restart.max_retries", "3"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceRestartPolicyConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    restart = {
+      policy      = "on-failure"
+      max_retries = 3
+    }
+}
+`, app, name)
+}
+
+func TestAccFlyMachineMetadata(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceMetadataConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "metadata.team", "platform"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "image_ref.repository", "library/nginx"),
+					resource.TestCheckResourceAttrSet("fly_machine.testMachine", "image_ref.digest"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceMetadataConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    metadata = {
+      team          = "platform"
+      process_group = "web"
+    }
+}
+`, app, name)
+}
+
+func TestAccFlyMachinePinImageDigest(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourcePinImageDigestConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "image", "nginx"),
+					resource.TestCheckResourceAttrSet("fly_machine.testMachine", "image_digest"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourcePinImageDigestConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    pin_image_digest = true
+}
+`, app, name)
+}
+
+// TestAccFlyMachinePinImageDigestConfigured pins an image that's configured with a digest already, the digest stays in
+// the image instead of showing as a diff on every plan
+func TestAccFlyMachinePinImageDigestConfigured(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourcePinImageDigestConfiguredConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("fly_machine.digestMachine", "image_digest", "fly_machine.testMachine", "image_digest"),
+					resource.TestMatchResourceAttr("fly_machine.digestMachine", "image", regexp.MustCompile("^nginx@sha256:")),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourcePinImageDigestConfiguredConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    pin_image_digest = true
+}
+
+resource "fly_machine" "digestMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s-digest"
+    image = "nginx@${fly_machine.testMachine.image_digest}"
+    pin_image_digest = true
+}
+`, app, name, app, name)
+}
+
+func TestAccFlyMachineFiles(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceFilesConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "files.0.guest_path", "/etc/nginx/conf.d/extra.conf"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "files.0.content", "# managed by terraform\n"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "files.1.content_base64", "aGVsbG8="),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceFilesConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    files = [
+      {
+        guest_path = "/etc/nginx/conf.d/extra.conf"
+        content    = "# managed by terraform\n"
+      },
+      {
+        guest_path     = "/usr/share/nginx/html/hello.txt"
+        content_base64 = "aGVsbG8="
+      }
+    ]
+}
+`, app, name)
+}
+
+func TestAccFlyMachineFilesInvalid(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config:      testFlyMachineResourceFilesInvalidConfig(rName),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("must set exactly one of content, content_base64 or secret_name"),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceFilesInvalidConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    files = [
+      {
+        guest_path     = "/usr/share/nginx/html/hello.txt"
+        content        = "hello"
+        content_base64 = "aGVsbG8="
+      }
+    ]
+}
+`, app, name)
+}
+
+func TestAccFlyMachineProcesses(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceProcessesConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "processes.0.name", "web"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "processes.1.cmd.0", "sleep"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "processes.1.env.ROLE", "sidecar"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceProcessesConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    processes = [
+      {
+        name = "web"
+      },
+      {
+        name       = "sidecar"
+        entrypoint = ["/bin/sh", "-c"]
+        cmd        = ["sleep", "infinity"]
+        user       = "nginx"
+        env = {
+          ROLE = "sidecar"
+        }
+      }
+    ]
+}
+`, app, name)
+}
+
+func TestAccFlyMachineSchedule(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceScheduleConfig(rName, "daily"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "schedule", "daily"),
+				),
+			},
+			{
+				Config: testFlyMachineResourceScheduleConfig(rName, "hourly"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "schedule", "hourly"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceScheduleConfig(name string, schedule string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "alpine"
+    cmd = ["echo", "hello"]
+    schedule = "%s"
+    restart = {
+      policy = "no"
+    }
+}
+`, app, name, schedule)
+}
+
+func TestAccFlyMachineStandbys(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceStandbysConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.standby", "standbys.#", "1"),
+					resource.TestCheckResourceAttrPair("fly_machine.standby", "standbys.0", "fly_machine.primary", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceStandbysConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "primary" {
+	app = "%s"
+	region = "ewr"
+	name = "%s-primary"
+    image = "nginx"
+}
+
+resource "fly_machine" "standby" {
+	app = "%s"
+	region = "ewr"
+	name = "%s-standby"
+    image = "nginx"
+    standbys = [fly_machine.primary.id]
+}
+`, app, name, app, name)
+}
+
+func TestAccFlyMachineBlueGreenUpdate(t *testing.T) {
+	t.Parallel()
+	var firstID string
+	var instanceID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceBlueGreenConfig("", "nginx:1.23", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "update_strategy", "blue-green"),
+					resource.TestCheckResourceAttrWith("fly_machine.testMachine", "id", func(id string) error {
+						firstID = id
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testFlyMachineResourceBlueGreenConfig("", "nginx:1.24", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "image", "nginx:1.24"),
+					resource.TestCheckResourceAttrWith("fly_machine.testMachine", "id", func(id string) error {
+						if id == firstID {
+							return fmt.Errorf("expected machine %s to be replaced", id)
+						}
+						return nil
+					}),
+					testAccCheckMachineInstance(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+			{
+				// cordoning isn't part of the machine config, the machine stays where it is
+				Config: testFlyMachineResourceBlueGreenConfig("", "nginx:1.24", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "cordoned", "true"),
+					testAccCheckMachineNotUpdated(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFlyMachineBlueGreenName(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config:      testFlyMachineResourceBlueGreenConfig(rName, "nginx:1.23", false),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("blue-green updates can't be used with a fixed name"),
+			},
+		},
+	})
+}
+
+// testFlyMachineResourceBlueGreenConfig leaves name out when it's empty, blue-green machines can't have a fixed one
+func testFlyMachineResourceBlueGreenConfig(name string, image string, cordoned bool) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+	nameAttr := ""
+	if name != "" {
+		nameAttr = fmt.Sprintf("name = %q", name)
+	}
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	%s
+    image = "%s"
+    cordoned = %t
+    update_strategy = "blue-green"
+    services = [
+      {
+        ports = [
+          {
+            port     = 80
+            handlers = ["http"]
+          }
+        ]
+        "protocol" : "tcp",
+        "internal_port" : 80
+      },
+    ]
+    checks = {
+      http = {
+        type     = "http"
+        port     = 80
+        interval = "10s"
+        timeout  = "2s"
+        path     = "/"
+      }
+    }
+}
+`, app, nameAttr, image, cordoned)
+}
+
+func TestAccFlyMachineTimeouts(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	var instanceID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceTimeoutsConfig(rName, "10m"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "timeouts.0.create", "10m"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "desired_state", "started"),
+					testAccCheckMachineInstance(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+			{
+				Config: testFlyMachineResourceTimeoutsConfig(rName, "5m"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "timeouts.0.update", "5m"),
+					testAccCheckMachineNotUpdated(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceTimeoutsConfig(name string, update string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+
+    timeouts {
+      create = "10m"
+      update = "%s"
+    }
+}
+`, app, name, update)
+}
+
+func TestAccFlyMachinesDataSource(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachinesDataSourceConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fly_machines.tagged", "machines.#", "1"),
+					resource.TestCheckResourceAttrPair("data.fly_machines.tagged", "machines.0.id", "fly_machine.testMachine", "id"),
+					resource.TestCheckResourceAttrPair("data.fly_machines.tagged", "machines.0.private_ip", "fly_machine.testMachine", "privateip"),
+					resource.TestCheckResourceAttr("data.fly_machines.tagged", "machines.0.metadata.test_run", rName),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachinesDataSourceConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    metadata = {
+      test_run = "%s"
+    }
+}
+
+data "fly_machines" "tagged" {
+  app = "%s"
+  metadata = {
+    test_run = "%s"
+  }
+  depends_on = [fly_machine.testMachine]
+}
+`, app, name, name, app, name)
+}
+
+func TestAccFlyMachineDataSource(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineDataSourceConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.fly_machine.byId", "privateip", "fly_machine.testMachine", "privateip"),
+					resource.TestCheckResourceAttrPair("data.fly_machine.byId", "name", "fly_machine.testMachine", "name"),
+					resource.TestCheckResourceAttrPair("data.fly_machine.byName", "id", "fly_machine.testMachine", "id"),
+					resource.TestCheckResourceAttr("data.fly_machine.byName", "env.TEST", "value"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineDataSourceConfig(name string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    env = {
+      TEST = "value"
+    }
+}
+
+data "fly_machine" "byId" {
+  app = "%s"
+  id  = fly_machine.testMachine.id
+}
+
+data "fly_machine" "byName" {
+  app        = "%s"
+  name       = "%s"
+  depends_on = [fly_machine.testMachine]
+}
+`, app, name, app, app, name)
+}
+
+func TestAccFlyMachineCordonAndSuspend(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	var instanceID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceCordonConfig(rName, true, "started"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "cordoned", "true"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "desired_state", "started"),
+					testAccCheckMachineInstance(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+			{
+				Config: testFlyMachineResourceCordonConfig(rName, false, "started"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "cordoned", "false"),
+					testAccCheckMachineNotUpdated(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+			{
+				Config: testFlyMachineResourceCordonConfig(rName, false, "suspended"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "cordoned", "false"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "desired_state", "suspended"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceCordonConfig(name string, cordoned bool, desiredState string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    cordoned = %t
+    desired_state = "%s"
+}
+`, app, name, cordoned, desiredState)
+}
+
+func TestAccFlyMachineTeardown(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	var instanceID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceTeardownConfig(rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "stop_signal", "SIGTERM"),
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "stop_timeout", "10s"),
+					testAccCheckMachineInstance(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+			{
+				Config: testFlyMachineResourceTeardownConfig(rName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "force_destroy", "true"),
+					testAccCheckMachineNotUpdated(t, "fly_machine.testMachine", &instanceID),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineResourceTeardownConfig(name string, force bool) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+    stop_signal = "SIGTERM"
+    stop_timeout = "10s"
+    force_destroy = %t
+
+    timeouts {
+      delete = "2m"
+    }
+}
+`, app, name, force)
+}
+
+func TestAccFlyMachineExec(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineExecConfig(rName, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine_exec.testExec", "stdout", "first\n"),
+					resource.TestCheckResourceAttr("fly_machine_exec.testExec", "exit_code", "0"),
+				),
+			},
+			{
+				Config: testFlyMachineExecConfig(rName, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine_exec.testExec", "stdout", "second\n"),
+				),
+			},
+		},
+	})
+}
+
+func testFlyMachineExecConfig(name string, run string) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_machine" "testMachine" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+    image = "nginx"
+}
+
+resource "fly_machine_exec" "testExec" {
+	app = fly_machine.testMachine.app
+	machine_id = fly_machine.testMachine.id
+	command = ["echo", "%s"]
+
+	triggers = {
+		run = "%s"
+	}
+}
+`, app, name, run, run)
+}
+
+func TestAccFlyMachineDeletedOutOfBand(t *testing.T) {
+	t.Parallel()
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	app := os.Getenv("FLY_TF_TEST_APP")
+	var machineID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyMachineResourceConfig(rName),
+				Check:  testAccCheckID("fly_machine.testMachine", &machineID),
+			},
+			{
+				// A machine destroyed outside terraform is dropped from state and created again
+				PreConfig: func() {
+					machineAPI, err := testAccMachineAPI(t)
+					if err != nil {
+						t.Fatalf("Failed to open tunnel: %s", err)
+					}
+					err = machineAPI.DeleteMachine(app, machineID, apiv1.MachineDestroyOptions{Force: true, Timeout: 2 * time.Minute})
+					if err != nil {
+						t.Fatalf("Failed to delete machine %s: %s", machineID, err)
+					}
+				},
+				Config: testFlyMachineResourceConfig(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_machine.testMachine", "name", rName),
+					testAccCheckReplaced("fly_machine.testMachine", &machineID),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckMachineInstance saves the instance id of a machine, which changes whenever its config is updated
+func testAccCheckMachineInstance(t *testing.T, name string, instanceID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		machine, err := testAccReadMachine(t, s, name)
+		if err != nil {
+			return err
+		}
+		*instanceID = machine.InstanceID
+		return nil
+	}
+}
+
+// testAccCheckMachineNotUpdated checks that the machine still runs the instance saved by testAccCheckMachineInstance,
+// so it wasn't restarted with a config update
+func testAccCheckMachineNotUpdated(t *testing.T, name string, instanceID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		machine, err := testAccReadMachine(t, s, name)
+		if err != nil {
+			return err
+		}
+		if machine.InstanceID != *instanceID {
+			return fmt.Errorf("machine %s was updated, instance %s is now %s", machine.ID, *instanceID, machine.InstanceID)
+		}
+		return nil
+	}
+}
+
+func testAccReadMachine(t *testing.T, s *terraform.State, name string) (*apiv1.MachineResponse, error) {
+	rs, ok := s.RootModule().Resources[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in state", name)
+	}
+	machineAPI, err := testAccMachineAPI(t)
+	if err != nil {
+		return nil, err
+	}
+	var machine apiv1.MachineResponse
+	err = machineAPI.ReadMachine(rs.Primary.Attributes["app"], rs.Primary.Attributes["id"], &machine)
+	if err != nil {
+		return nil, err
+	}
+	return &machine, nil
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"github.com/fly-apps/terraform-provider-fly/pkg/apiv1"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfsdkprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ tfsdkprovider.DataSourceType = machinesDataSourceType{}
+var _ datasource.DataSource = machinesDataSource{}
+
+type machinesDataSourceType struct{}
+
+// Matches getSchema
+type machinesDataSourceOutput struct {
+	App      types.String                `tfsdk:"app"`
+	Region   types.String                `tfsdk:"region"`
+	State    types.String                `tfsdk:"state"`
+	Metadata map[string]string           `tfsdk:"metadata"`
+	Machines []machinesDataSourceMachine `tfsdk:"machines"`
+}
+
+type machinesDataSourceMachine struct {
+	Id        types.String      `tfsdk:"id"`
+	Name      types.String      `tfsdk:"name"`
+	Region    types.String      `tfsdk:"region"`
+	State     types.String      `tfsdk:"state"`
+	PrivateIP types.String      `tfsdk:"private_ip"`
+	Image     types.String      `tfsdk:"image"`
+	Metadata  map[string]string `tfsdk:"metadata"`
+}
+
+func (m machinesDataSourceType) GetSchema(context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Machines in an app, optionally filtered by region, state and metadata",
+		Attributes: map[string]tfsdk.Attribute{
+			"app": {
+				MarkdownDescription: "Name of app",
+				Required:            true,
+				Type:                types.StringType,
+			},
+			"region": {
+				MarkdownDescription: "Only list machines in this region",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"state": {
+				MarkdownDescription: "Only list machines in these states, comma separated like `started,stopped`",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"metadata": {
+				MarkdownDescription: "Only list machines with all of these metadata values",
+				Optional:            true,
+				Type:                types.MapType{ElemType: types.StringType},
+			},
+			"machines": {
+				MarkdownDescription: "Matching machines",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"id": {
+						MarkdownDescription: "machine id",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"name": {
+						MarkdownDescription: "machine name",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"region": {
+						MarkdownDescription: "machine region",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"state": {
+						MarkdownDescription: "Current state of the machine, like `started` or `stopped`",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"private_ip": {
+						MarkdownDescription: "Private IP",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"image": {
+						MarkdownDescription: "Image the machine runs",
+						Computed:            true,
+						Type:                types.StringType,
+					},
+					"metadata": {
+						MarkdownDescription: "Machine metadata",
+						Computed:            true,
+						Type:                types.MapType{ElemType: types.StringType},
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (m machinesDataSourceType) NewDataSource(_ context.Context, in tfsdkprovider.Provider) (datasource.DataSource, diag.Diagnostics) {
+	provider, diags := convertProviderType(in)
+
+	return machinesDataSource{
+		provider: provider,
+	}, diags
+}
+
+func (m machinesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data machinesDataSourceOutput
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	machineAPI := apiv1.NewMachineAPI(m.provider.httpClient, m.provider.httpEndpoint)
+
+	machines, err := machineAPI.ListMachines(data.App.Value, apiv1.MachineListFilters{
+		Region:   data.Region.Value,
+		State:    data.State.Value,
+		Metadata: data.Metadata,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list machines", err.Error())
+		return
+	}
+
+	data.Machines = make([]machinesDataSourceMachine, 0)
+	for _, machine := range machines {
+		data.Machines = append(data.Machines, machinesDataSourceMachine{
+			Id:        types.String{Value: machine.ID},
+			Name:      types.String{Value: machine.Name},
+			Region:    types.String{Value: machine.Region},
+			State:     types.String{Value: machine.State},
+			PrivateIP: types.String{Value: machine.PrivateIP},
+			Image:     types.String{Value: machine.Config.Image},
+			Metadata:  machine.Config.Metadata,
+		})
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
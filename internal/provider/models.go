@@ -3,6 +3,9 @@ package provider
 type appDataSource struct {
 	provider provider
 }
+type appsDataSource struct {
+	provider provider
+}
 type certDataSource struct {
 	provider provider
 }
@@ -12,3 +15,9 @@ type ipDataSource struct {
 type volumeDataSource struct {
 	provider provider
 }
+type machineDataSource struct {
+	provider provider
+}
+type machinesDataSource struct {
+	provider provider
+}
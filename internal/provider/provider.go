@@ -121,20 +121,25 @@ func (p *provider) Configure(ctx context.Context, req tfsdkprovider.ConfigureReq
 func (p *provider) GetResources(ctx context.Context) (map[string]tfsdkprovider.ResourceType, diag.Diagnostics) {
 
 	return map[string]tfsdkprovider.ResourceType{
-		"fly_app":     flyAppResourceType{},
-		"fly_volume":  flyVolumeResourceType{},
-		"fly_ip":      flyIpResourceType{},
-		"fly_cert":    flyCertResourceType{},
-		"fly_machine": flyMachineResourceType{},
+		"fly_app":          flyAppResourceType{},
+		"fly_app_secrets":  flyAppSecretsResourceType{},
+		"fly_volume":       flyVolumeResourceType{},
+		"fly_ip":           flyIpResourceType{},
+		"fly_cert":         flyCertResourceType{},
+		"fly_machine":      flyMachineResourceType{},
+		"fly_machine_exec": flyMachineExecResourceType{},
 	}, nil
 }
 
 func (p *provider) GetDataSources(ctx context.Context) (map[string]tfsdkprovider.DataSourceType, diag.Diagnostics) {
 	return map[string]tfsdkprovider.DataSourceType{
-		"fly_app":    appDataSourceType{},
-		"fly_cert":   certDataSourceType{},
-		"fly_ip":     ipDataSourceType{},
-		"fly_volume": volumeDataSourceType{},
+		"fly_app":      appDataSourceType{},
+		"fly_apps":     appsDataSourceType{},
+		"fly_cert":     certDataSourceType{},
+		"fly_ip":       ipDataSourceType{},
+		"fly_volume":   volumeDataSourceType{},
+		"fly_machine":  machineDataSourceType{},
+		"fly_machines": machinesDataSourceType{},
 	}, nil
 }
 
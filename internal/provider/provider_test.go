@@ -1,10 +1,22 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"github.com/Khan/genqlient/graphql"
+	providerGraphql "github.com/fly-apps/terraform-provider-fly/graphql"
+	"github.com/fly-apps/terraform-provider-fly/internal/utils"
+	"github.com/fly-apps/terraform-provider-fly/internal/wg"
+	"github.com/fly-apps/terraform-provider-fly/pkg/apiv1"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	hreq "github.com/imroc/req/v3"
+	"net/http"
 	"os"
 	"testing"
+	"time"
 )
 
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
@@ -21,3 +33,53 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatalf("Need app in FLY_TF_TEST_APP")
 	}
 }
+
+// testAccCheckID saves the id of a resource so later steps can tell whether it was replaced
+func testAccCheckID(name string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found in state", name)
+		}
+		*id = rs.Primary.Attributes["id"]
+		return nil
+	}
+}
+
+func testAccCheckReplaced(name string, priorID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found in state", name)
+		}
+		if rs.Primary.Attributes["id"] == *priorID {
+			return fmt.Errorf("%s still has id %s, expected it to be replaced", name, *priorID)
+		}
+		return nil
+	}
+}
+
+// testAccGraphqlClient is a client for the graphql api, for tests that look at or change things behind terraform's back
+func testAccGraphqlClient() graphql.Client {
+	h := http.Client{Timeout: 60 * time.Second, Transport: &utils.Transport{UnderlyingTransport: http.DefaultTransport, Token: os.Getenv("FLY_API_TOKEN"), Ctx: context.Background()}}
+	return graphql.NewClient("https://api.fly.io/graphql", &h)
+}
+
+// testAccMachineAPI opens a tunnel the same way the provider does, for tests that look at or change machines behind
+// terraform's back. The tunnel is closed when the test ends.
+func testAccMachineAPI(t *testing.T) (*apiv1.MachineAPI, error) {
+	token := os.Getenv("FLY_API_TOKEN")
+	client := testAccGraphqlClient()
+	org, err := providerGraphql.Organization(context.Background(), client, testAccOrg)
+	if err != nil {
+		return nil, err
+	}
+	tunnel, err := wg.Establish(context.Background(), org.Organization.Id, "ewr", token, &client)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() { _ = tunnel.Down() })
+
+	httpClient := hreq.C().SetCommonHeader("Authorization", "Bearer "+token).SetDial(tunnel.NetStack().DialContext)
+	return apiv1.NewMachineAPI(httpClient, "_api.internal:4280"), nil
+}
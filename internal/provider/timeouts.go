@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/fly-apps/terraform-provider-fly/internal/provider/validators"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// How long resources wait on the orchestrator when there is no timeouts block
+const defaultTimeout = 5 * time.Minute
+
+// TfTimeouts is the `timeouts` block, matching the one sdk based providers have
+type TfTimeouts struct {
+	Create types.String `tfsdk:"create"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
+}
+
+func timeoutsBlock() tfsdk.Block {
+	return tfsdk.Block{
+		MarkdownDescription: "How long to wait for create, update and delete to finish before failing. Defaults to 5m",
+		NestingMode:         tfsdk.BlockNestingModeList,
+		MaxItems:            1,
+		Attributes: map[string]tfsdk.Attribute{
+			"create": {
+				MarkdownDescription: "Timeout for create, like `10m`",
+				Optional:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					validators.Duration(),
+				},
+			},
+			"update": {
+				MarkdownDescription: "Timeout for update, like `10m`",
+				Optional:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					validators.Duration(),
+				},
+			},
+			"delete": {
+				MarkdownDescription: "Timeout for delete, like `10m`",
+				Optional:            true,
+				Type:                types.StringType,
+				Validators: []tfsdk.AttributeValidator{
+					validators.Duration(),
+				},
+			},
+		},
+	}
+}
+
+func createTimeout(timeouts []TfTimeouts) time.Duration {
+	if len(timeouts) == 0 {
+		return defaultTimeout
+	}
+	return parseTimeout(timeouts[0].Create)
+}
+
+func updateTimeout(timeouts []TfTimeouts) time.Duration {
+	if len(timeouts) == 0 {
+		return defaultTimeout
+	}
+	return parseTimeout(timeouts[0].Update)
+}
+
+func deleteTimeout(timeouts []TfTimeouts) time.Duration {
+	if len(timeouts) == 0 {
+		return defaultTimeout
+	}
+	return parseTimeout(timeouts[0].Delete)
+}
+
+// parseTimeout falls back to the default for unset values, invalid ones are rejected by the validator
+func parseTimeout(value types.String) time.Duration {
+	if value.Null || value.Unknown {
+		return defaultTimeout
+	}
+	timeout, err := time.ParseDuration(value.Value)
+	if err != nil {
+		return defaultTimeout
+	}
+	return timeout
+}
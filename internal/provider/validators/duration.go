@@ -0,0 +1,51 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// durationValidator is an attribute validator that ensures a types.StringType
+// attribute is a duration that time.ParseDuration understands, like `30s` or
+// `5m`. Null and unknown values are not validated.
+type durationValidator struct{}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v durationValidator) Description(ctx context.Context) string {
+	return "Value must be a duration like 30s, 5m or 1h30m"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return "Value must be a duration like `30s`, `5m` or `1h30m`"
+}
+
+// Validate runs the logic of the validator.
+func (v durationValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	var str types.String
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &str)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	if str.Null || str.Unknown {
+		return
+	}
+
+	if _, err := time.ParseDuration(str.Value); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.AttributePath,
+			"Invalid duration",
+			fmt.Sprintf("%s, got: %q", v.Description(ctx), str.Value),
+		)
+	}
+}
+
+func Duration() durationValidator {
+	return durationValidator{}
+}
@@ -0,0 +1,59 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// stringOneOfValidator is an attribute validator that ensures a
+// types.StringType attribute is set to one of a fixed list of values. Null and
+// unknown values are not validated.
+type stringOneOfValidator struct {
+	Values []string
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v stringOneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("Value must be one of: %s", strings.Join(v.Values, ", "))
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Value must be one of: `%s`", strings.Join(v.Values, "`, `"))
+}
+
+// Validate runs the logic of the validator.
+func (v stringOneOfValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	var str types.String
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &str)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	if str.Null || str.Unknown {
+		return
+	}
+
+	for _, value := range v.Values {
+		if str.Value == value {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.AttributePath,
+		"Invalid attribute value",
+		fmt.Sprintf("%s, got: %q", v.Description(ctx), str.Value),
+	)
+}
+
+func StringOneOf(values ...string) stringOneOfValidator {
+	return stringOneOfValidator{
+		Values: values,
+	}
+}
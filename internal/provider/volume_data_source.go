@@ -24,6 +24,7 @@ type volumeDataSourceOutput struct {
 	Appid      types.String `tfsdk:"app"`
 	Region     types.String `tfsdk:"region"`
 	Internalid types.String `tfsdk:"internalid"`
+	Encrypted  types.Bool   `tfsdk:"encrypted"`
 }
 
 func (v volumeDataSourceType) GetSchema(context.Context) (tfsdk.Schema, diag.Diagnostics) {
@@ -61,6 +62,11 @@ func (v volumeDataSourceType) GetSchema(context.Context) (tfsdk.Schema, diag.Dia
 				Type:                types.StringType,
 				Required:            true,
 			},
+			"encrypted": {
+				MarkdownDescription: "Whether the volume is encrypted at rest",
+				Type:                types.BoolType,
+				Computed:            true,
+			},
 		},
 	}, nil
 }
@@ -94,6 +100,7 @@ func (v volumeDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		Appid:      types.String{Value: data.Appid.Value},
 		Region:     types.String{Value: query.App.Volume.Region},
 		Internalid: types.String{Value: query.App.Volume.InternalId},
+		Encrypted:  types.Bool{Value: query.App.Volume.Encrypted},
 	}
 
 	if resp.Diagnostics.HasError() {
@@ -2,10 +2,13 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/fly-apps/terraform-provider-fly/graphql"
+	"github.com/fly-apps/terraform-provider-fly/pkg/apiv1"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	tfsdkprovider "github.com/hashicorp/terraform-plugin-framework/provider"
@@ -13,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
 var _ tfsdkprovider.ResourceType = flyVolumeResourceType{}
@@ -32,6 +36,11 @@ type flyVolumeResourceData struct {
 	Appid      types.String `tfsdk:"app"`
 	Region     types.String `tfsdk:"region"`
 	Internalid types.String `tfsdk:"internalid"`
+
+	Encrypted         types.Bool   `tfsdk:"encrypted"`
+	RequireUniqueZone types.Bool   `tfsdk:"require_unique_zone"`
+	SnapshotId        types.String `tfsdk:"snapshot_id"`
+	SnapshotRetention types.Int64  `tfsdk:"snapshot_retention"`
 }
 
 func (t flyVolumeResourceType) GetSchema(context.Context) (tfsdk.Schema, diag.Diagnostics) {
@@ -70,10 +79,46 @@ func (t flyVolumeResourceType) GetSchema(context.Context) (tfsdk.Schema, diag.Di
 				Computed:            true,
 				Optional:            true,
 			},
+			"encrypted": {
+				MarkdownDescription: "Whether the volume is encrypted at rest, defaults to true",
+				Type:                types.BoolType,
+				Computed:            true,
+				Optional:            true,
+				PlanModifiers:       tfsdk.AttributePlanModifiers{resource.RequiresReplace(), resource.UseStateForUnknown()},
+			},
+			"require_unique_zone": {
+				MarkdownDescription: "Place the volume in a zone not used by other volumes of the app. Only used when creating the volume, " +
+					"the api doesn't report it so it's kept as configured and is null for imported volumes",
+				Type:          types.BoolType,
+				Optional:      true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{requiresReplaceIfSet()},
+			},
+			"snapshot_id": {
+				MarkdownDescription: "ID of a snapshot to restore into the new volume. Only used when creating the volume, " +
+					"the api doesn't report it so it's kept as configured and is null for imported volumes. " +
+					"Requires the machines api, like `fly_machine`",
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: tfsdk.AttributePlanModifiers{requiresReplaceIfSet()},
+			},
+			"snapshot_retention": {
+				MarkdownDescription: "Days to keep daily snapshots of the volume. Only read back from the api while it's set, " +
+					"set it on an imported volume to start managing it. Requires the machines api, like `fly_machine`",
+				Type:     types.Int64Type,
+				Optional: true,
+			},
 		},
 	}, nil
 }
 
+// requiresReplaceIfSet replaces the volume when a create only option changes, but not when it's set for the first time
+// on an imported volume or removed from the config, since the api can't tell what the volume was created with
+func requiresReplaceIfSet() tfsdk.AttributePlanModifier {
+	return resource.RequiresReplaceIf(func(ctx context.Context, state, config attr.Value, path path.Path) (bool, diag.Diagnostics) {
+		return state != nil && !state.IsNull() && config != nil && !config.IsNull(), nil
+	}, "Changing a create only option replaces the volume", "Changing a create only option replaces the volume")
+}
+
 func (t flyVolumeResourceType) NewResource(ctx context.Context, in tfsdkprovider.Provider) (resource.Resource, diag.Diagnostics) {
 	provider, diags := convertProviderType(in)
 
@@ -88,9 +133,29 @@ func (vr flyVolumeResource) Create(ctx context.Context, req resource.CreateReque
 	diags := req.Plan.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 
-	q, err := graphql.CreateVolume(context.Background(), *vr.provider.client, data.Appid.Value, data.Name.Value, data.Region.Value, int(data.Size.Value))
+	var encrypted, requireUniqueZone *bool
+	if !data.Encrypted.Null && !data.Encrypted.Unknown {
+		encrypted = &data.Encrypted.Value
+	}
+	if !data.RequireUniqueZone.Null {
+		requireUniqueZone = &data.RequireUniqueZone.Value
+	}
+
+	// Graphql can't restore snapshots or set retention, volumes that need either are created through the machines api
+	if !data.SnapshotId.Null || !data.SnapshotRetention.Null {
+		_, err := validateOpenTunnel(vr.provider)
+		if err != nil {
+			resp.Diagnostics.AddError("fly wireguard tunnel must be open to use snapshot_id or snapshot_retention", err.Error())
+			return
+		}
+		vr.createWithMachineAPI(ctx, &data, encrypted, requireUniqueZone, resp)
+		return
+	}
+
+	q, err := graphql.CreateVolume(context.Background(), *vr.provider.client, data.Appid.Value, data.Name.Value, data.Region.Value, int(data.Size.Value), encrypted, requireUniqueZone)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create volume", err.Error())
+		return
 	}
 
 	data = flyVolumeResourceData{
@@ -100,6 +165,11 @@ func (vr flyVolumeResource) Create(ctx context.Context, req resource.CreateReque
 		Appid:      types.String{Value: data.Appid.Value},
 		Region:     types.String{Value: q.CreateVolume.Volume.Region},
 		Internalid: types.String{Value: q.CreateVolume.Volume.InternalId},
+
+		Encrypted:         types.Bool{Value: q.CreateVolume.Volume.Encrypted},
+		RequireUniqueZone: data.RequireUniqueZone,
+		SnapshotId:        data.SnapshotId,
+		SnapshotRetention: data.SnapshotRetention,
 	}
 
 	tflog.Info(ctx, fmt.Sprintf("%+v", data))
@@ -111,6 +181,58 @@ func (vr flyVolumeResource) Create(ctx context.Context, req resource.CreateReque
 	}
 }
 
+func (vr flyVolumeResource) createWithMachineAPI(ctx context.Context, data *flyVolumeResourceData, encrypted *bool, requireUniqueZone *bool, resp *resource.CreateResponse) {
+	machineAPI := apiv1.NewMachineAPI(vr.provider.httpClient, vr.provider.httpEndpoint)
+
+	createReq := apiv1.CreateVolumeRequest{
+		Name:              data.Name.Value,
+		Region:            data.Region.Value,
+		SizeGb:            int(data.Size.Value),
+		Encrypted:         encrypted,
+		RequireUniqueZone: requireUniqueZone,
+		SnapshotID:        data.SnapshotId.Value,
+	}
+	if !data.SnapshotRetention.Null {
+		retention := int(data.SnapshotRetention.Value)
+		createReq.SnapshotRetention = &retention
+	}
+
+	volume, err := machineAPI.CreateVolume(data.Appid.Value, createReq)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create volume", err.Error())
+		return
+	}
+
+	// The machines api id is the internal id, deleting the volume takes the graphql id
+	query, err := graphql.VolumeQuery(context.Background(), *vr.provider.client, data.Appid.Value, volume.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read created volume", err.Error())
+		return
+	}
+
+	*data = flyVolumeResourceData{
+		Id:         types.String{Value: query.App.Volume.Id},
+		Name:       types.String{Value: volume.Name},
+		Size:       types.Int64{Value: int64(volume.SizeGb)},
+		Appid:      types.String{Value: data.Appid.Value},
+		Region:     types.String{Value: volume.Region},
+		Internalid: types.String{Value: volume.ID},
+
+		Encrypted:         types.Bool{Value: volume.Encrypted},
+		RequireUniqueZone: data.RequireUniqueZone,
+		SnapshotId:        data.SnapshotId,
+		SnapshotRetention: data.SnapshotRetention,
+	}
+	if !data.SnapshotRetention.Null {
+		data.SnapshotRetention = types.Int64{Value: int64(volume.SnapshotRetention)}
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("%+v", data))
+
+	diags := resp.State.Set(ctx, data)
+	resp.Diagnostics.Append(diags...)
+}
+
 func (vr flyVolumeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data flyVolumeResourceData
 
@@ -121,8 +243,44 @@ func (vr flyVolumeResource) Read(ctx context.Context, req resource.ReadRequest,
 	app := data.Appid.Value
 
 	query, err := graphql.VolumeQuery(context.Background(), *vr.provider.client, app, internalId)
-	if err != nil {
+	var errList gqlerror.List
+	if errors.As(err, &errList) {
+		for _, err := range errList {
+			if err.Message == "Could not resolve " {
+				tflog.Info(ctx, fmt.Sprintf("Volume %s not found, removing it from state", internalId))
+				resp.State.RemoveResource(ctx)
+				return
+			}
+			resp.Diagnostics.AddError(err.Message, err.Path.String())
+		}
+		return
+	} else if err != nil {
 		resp.Diagnostics.AddError("Read: query failed", err.Error())
+		return
+	}
+
+	// Deleted volumes linger for a while in pending_destroy before they are gone
+	if query.App.Volume.Id == "" || query.App.Volume.State == "pending_destroy" || query.App.Volume.State == "destroyed" {
+		tflog.Info(ctx, fmt.Sprintf("Volume %s is gone (%s), removing it from state", internalId, query.App.Volume.State))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Retention is only known to the machines api, so it's only read back when terraform manages it
+	snapshotRetention := data.SnapshotRetention
+	if !snapshotRetention.Null {
+		_, err := validateOpenTunnel(vr.provider)
+		if err != nil {
+			resp.Diagnostics.AddError("fly wireguard tunnel must be open to read snapshot_retention", err.Error())
+			return
+		}
+		machineAPI := apiv1.NewMachineAPI(vr.provider.httpClient, vr.provider.httpEndpoint)
+		volume, err := machineAPI.ReadVolume(app, internalId)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read volume snapshot retention", err.Error())
+			return
+		}
+		snapshotRetention = types.Int64{Value: int64(volume.SnapshotRetention)}
 	}
 
 	data = flyVolumeResourceData{
@@ -132,6 +290,12 @@ func (vr flyVolumeResource) Read(ctx context.Context, req resource.ReadRequest,
 		Appid:      types.String{Value: data.Appid.Value},
 		Region:     types.String{Value: query.App.Volume.Region},
 		Internalid: types.String{Value: query.App.Volume.InternalId},
+
+		Encrypted: types.Bool{Value: query.App.Volume.Encrypted},
+		// Only used when creating the volume, the api doesn't return them
+		RequireUniqueZone: data.RequireUniqueZone,
+		SnapshotId:        data.SnapshotId,
+		SnapshotRetention: snapshotRetention,
 	}
 
 	diags = resp.State.Set(ctx, &data)
@@ -141,9 +305,51 @@ func (vr flyVolumeResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 }
 
+// Update only changes snapshot retention, every other option is fixed once the volume is created
 func (vr flyVolumeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError("The fly api does not allow updating volumes once created", "Try deleting and then recreating a volume with new options")
-	return
+	var plan flyVolumeResourceData
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	var state flyVolumeResourceData
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Name.Value != state.Name.Value || plan.Size.Value != state.Size.Value || plan.Region.Value != state.Region.Value || plan.Appid.Value != state.Appid.Value {
+		resp.Diagnostics.AddError("The fly api does not allow updating volumes once created", "Try deleting and then recreating a volume with new options")
+		return
+	}
+
+	// Removing snapshot_retention leaves the volume's retention as it is and stops managing it
+	if !plan.SnapshotRetention.Null && plan.SnapshotRetention.Value != state.SnapshotRetention.Value {
+		_, err := validateOpenTunnel(vr.provider)
+		if err != nil {
+			resp.Diagnostics.AddError("fly wireguard tunnel must be open to update snapshot_retention", err.Error())
+			return
+		}
+		machineAPI := apiv1.NewMachineAPI(vr.provider.httpClient, vr.provider.httpEndpoint)
+		retention := int(plan.SnapshotRetention.Value)
+		volume, err := machineAPI.UpdateVolume(state.Appid.Value, state.Internalid.Value, apiv1.UpdateVolumeRequest{
+			SnapshotRetention: &retention,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update volume snapshot retention", err.Error())
+			return
+		}
+		plan.SnapshotRetention = types.Int64{Value: int64(volume.SnapshotRetention)}
+	}
+	state.SnapshotRetention = plan.SnapshotRetention
+	// Create only options set on an imported volume are kept as configured
+	state.RequireUniqueZone = plan.RequireUniqueZone
+	state.SnapshotId = plan.SnapshotId
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (vr flyVolumeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
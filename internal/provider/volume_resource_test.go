@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	providerGraphql "github.com/fly-apps/terraform-provider-fly/graphql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"os"
+	"testing"
+)
+
+func TestAccFlyVolumeOptions(t *testing.T) {
+	t.Parallel()
+	rName := "acctest_" + acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	var volumeID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyVolumeResourceOptionsConfig(rName, 5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_volume.testVolume", "name", rName),
+					resource.TestCheckResourceAttr("fly_volume.testVolume", "encrypted", "true"),
+					resource.TestCheckResourceAttr("fly_volume.testVolume", "require_unique_zone", "false"),
+					resource.TestCheckResourceAttr("fly_volume.testVolume", "snapshot_retention", "5"),
+					testAccCheckID("fly_volume.testVolume", &volumeID),
+				),
+			},
+			{
+				Config: testFlyVolumeResourceOptionsConfig(rName, 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_volume.testVolume", "snapshot_retention", "10"),
+					resource.TestCheckResourceAttrPtr("fly_volume.testVolume", "id", &volumeID),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFlyVolumeDeletedOutOfBand(t *testing.T) {
+	t.Parallel()
+	rName := "acctest_" + acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	var volumeID string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testFlyVolumeResourceOptionsConfig(rName, 5),
+				Check:  testAccCheckID("fly_volume.testVolume", &volumeID),
+			},
+			{
+				// A volume deleted outside terraform is dropped from state and created again
+				PreConfig: func() {
+					_, err := providerGraphql.DeleteVolume(context.Background(), testAccGraphqlClient(), volumeID)
+					if err != nil {
+						t.Fatalf("Failed to delete volume %s: %s", volumeID, err)
+					}
+				},
+				Config: testFlyVolumeResourceOptionsConfig(rName, 5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fly_volume.testVolume", "name", rName),
+					testAccCheckReplaced("fly_volume.testVolume", &volumeID),
+				),
+			},
+		},
+	})
+}
+
+func testFlyVolumeResourceOptionsConfig(name string, snapshotRetention int) string {
+	app := os.Getenv("FLY_TF_TEST_APP")
+
+	return fmt.Sprintf(`
+provider "fly" {
+  useinternaltunnel    = true
+  internaltunnelorg    = "fly-terraform-ci"
+  internaltunnelregion = "ewr"
+}
+
+resource "fly_volume" "testVolume" {
+	app = "%s"
+	region = "ewr"
+	name = "%s"
+	size = 1
+	encrypted = true
+	require_unique_zone = false
+	snapshot_retention = %d
+}
+`, app, name, snapshotRetention)
+}
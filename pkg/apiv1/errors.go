@@ -0,0 +1,108 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	hreq "github.com/imroc/req/v3"
+	"net/http"
+	"time"
+)
+
+// APIError is a failed response from the machines api
+type APIError struct {
+	StatusCode int
+	Status     string
+	Code       string
+	Message    string
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Status
+	if e.Code != "" {
+		msg += " (" + e.Code + ")"
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" [request id: %s]", e.RequestID)
+	}
+	return msg
+}
+
+// newAPIError reads the error out of a response. The api reports errors as `{"error": "..."}`, some endpoints also
+// add a `status` or `code`, anything else ends up as the raw body.
+func newAPIError(res *hreq.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		RequestID:  res.GetHeader("Fly-Request-Id"),
+	}
+
+	body := res.Bytes()
+	var decoded struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+		Code    string `json:"code"`
+		Status  string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		apiErr.Message = string(body)
+		return apiErr
+	}
+
+	apiErr.Message = decoded.Error
+	if apiErr.Message == "" {
+		apiErr.Message = decoded.Message
+	}
+	apiErr.Code = decoded.Code
+	if apiErr.Code == "" {
+		apiErr.Code = decoded.Status
+	}
+	return apiErr
+}
+
+// IsNotFound reports whether err is the api saying the machine (or app) doesn't exist
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsLeaseConflict reports whether err is the api refusing a request because someone else holds the machine's lease
+func IsLeaseConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+const (
+	retryCount      = 5
+	retryMinBackoff = 500 * time.Millisecond
+	retryMaxBackoff = 15 * time.Second
+)
+
+// request starts a request with the shared retry policy: rate limits, server errors and lease conflicts are retried
+// with capped exponential backoff and jitter
+func (a *MachineAPI) request() *hreq.Request {
+	return a.httpClient.R().
+		SetRetryCount(retryCount).
+		SetRetryBackoffInterval(retryMinBackoff, retryMaxBackoff).
+		SetRetryCondition(isRetryable)
+}
+
+func isRetryable(res *hreq.Response, err error) bool {
+	if err != nil || res == nil || res.Response == nil {
+		return false
+	}
+	return isRateLimited(res, err) || res.StatusCode == http.StatusConflict || res.StatusCode >= http.StatusInternalServerError
+}
+
+// isRateLimited is the retry condition for requests that aren't safe to repeat after a server error, like creating
+// a machine
+func isRateLimited(res *hreq.Response, err error) bool {
+	if err != nil || res == nil || res.Response == nil {
+		return false
+	}
+	return res.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,125 @@
+package apiv1
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hreq "github.com/imroc/req/v3"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		requestID  string
+		want       APIError
+		wantString string
+	}{
+		{
+			name:       "error field",
+			statusCode: http.StatusNotFound,
+			body:       `{"error": "machine not found"}`,
+			want:       APIError{StatusCode: http.StatusNotFound, Status: "404 Not Found", Message: "machine not found"},
+			wantString: "404 Not Found: machine not found",
+		},
+		{
+			name:       "message and code",
+			statusCode: http.StatusBadRequest,
+			body:       `{"message": "invalid config", "code": "invalid_config"}`,
+			want:       APIError{StatusCode: http.StatusBadRequest, Status: "400 Bad Request", Code: "invalid_config", Message: "invalid config"},
+			wantString: "400 Bad Request (invalid_config): invalid config",
+		},
+		{
+			name:       "error wins over message and code over status",
+			statusCode: http.StatusUnprocessableEntity,
+			body:       `{"error": "bad image", "message": "ignored", "code": "image", "status": "ignored"}`,
+			want:       APIError{StatusCode: http.StatusUnprocessableEntity, Status: "422 Unprocessable Entity", Code: "image", Message: "bad image"},
+			wantString: "422 Unprocessable Entity (image): bad image",
+		},
+		{
+			name:       "status as code",
+			statusCode: http.StatusConflict,
+			body:       `{"error": "lease held", "status": "conflict"}`,
+			want:       APIError{StatusCode: http.StatusConflict, Status: "409 Conflict", Code: "conflict", Message: "lease held"},
+			wantString: "409 Conflict (conflict): lease held",
+		},
+		{
+			name:       "raw body",
+			statusCode: http.StatusBadGateway,
+			body:       "upstream unavailable",
+			want:       APIError{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway", Message: "upstream unavailable"},
+			wantString: "502 Bad Gateway: upstream unavailable",
+		},
+		{
+			name:       "empty body",
+			statusCode: http.StatusForbidden,
+			want:       APIError{StatusCode: http.StatusForbidden, Status: "403 Forbidden"},
+			wantString: "403 Forbidden",
+		},
+		{
+			name:       "request id",
+			statusCode: http.StatusNotFound,
+			body:       `{"error": "app not found"}`,
+			requestID:  "01GRF8P7",
+			want:       APIError{StatusCode: http.StatusNotFound, Status: "404 Not Found", Message: "app not found", RequestID: "01GRF8P7"},
+			wantString: "404 Not Found: app not found [request id: 01GRF8P7]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.requestID != "" {
+					w.Header().Set("Fly-Request-Id", tt.requestID)
+				}
+				w.WriteHeader(tt.statusCode)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			res, err := hreq.C().R().Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := newAPIError(res)
+			if *got != tt.want {
+				t.Errorf("newAPIError() = %+v, want %+v", *got, tt.want)
+			}
+			if got.Error() != tt.wantString {
+				t.Errorf("Error() = %q, want %q", got.Error(), tt.wantString)
+			}
+		})
+	}
+}
+
+func TestIsNotFoundAndIsLeaseConflict(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		notFound      bool
+		leaseConflict bool
+	}{
+		{name: "nil", err: nil},
+		{name: "other error", err: fmt.Errorf("connection refused")},
+		{name: "not found", err: &APIError{StatusCode: http.StatusNotFound}, notFound: true},
+		{name: "conflict", err: &APIError{StatusCode: http.StatusConflict}, leaseConflict: true},
+		{name: "server error", err: &APIError{StatusCode: http.StatusInternalServerError}},
+		{name: "wrapped not found", err: fmt.Errorf("reading machine: %w", &APIError{StatusCode: http.StatusNotFound}), notFound: true},
+		{name: "wrapped conflict", err: fmt.Errorf("updating machine: %w", &APIError{StatusCode: http.StatusConflict}), leaseConflict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNotFound(tt.err); got != tt.notFound {
+				t.Errorf("IsNotFound() = %v, want %v", got, tt.notFound)
+			}
+			if got := IsLeaseConflict(tt.err); got != tt.leaseConflict {
+				t.Errorf("IsLeaseConflict() = %v, want %v", got, tt.leaseConflict)
+			}
+		})
+	}
+}
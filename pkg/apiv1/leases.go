@@ -0,0 +1,93 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// How long leases taken by WithLease last, they are refreshed at half this interval for as long as they are held
+const leaseTTL = 30
+
+// LeaseHeldError is returned when the machine is leased by someone else, like a flyctl deploy or another terraform run
+type LeaseHeldError struct {
+	App       string
+	ID        string
+	Owner     string
+	ExpiresAt int64
+}
+
+func (e *LeaseHeldError) Error() string {
+	msg := fmt.Sprintf("machine %s in app %s is leased by another owner", e.ID, e.App)
+	if e.Owner != "" {
+		msg = fmt.Sprintf("machine %s in app %s is leased by %s", e.ID, e.App, e.Owner)
+	}
+	if e.ExpiresAt != 0 {
+		msg += fmt.Sprintf(" until %s", time.Unix(e.ExpiresAt, 0).UTC().Format(time.RFC3339))
+	}
+	return msg
+}
+
+// leaseError turns a failed lease request into a LeaseHeldError when the machine is already leased
+func leaseError(app string, id string, statusCode int, body []byte, apiErr error) error {
+	var lease MachineLease
+	_ = json.Unmarshal(body, &lease)
+	if statusCode == http.StatusConflict || lease.Data.Owner != "" {
+		return &LeaseHeldError{App: app, ID: id, Owner: lease.Data.Owner, ExpiresAt: lease.Data.ExpiresAt}
+	}
+	return apiErr
+}
+
+func (a *MachineAPI) refreshLease(lease MachineLease, app string, id string, ttl int) error {
+	refreshResponse, err := a.request().SetHeader(NonceHeader, lease.Data.Nonce).Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/lease/?ttl=%d", a.endpoint, app, id, ttl))
+	if err != nil {
+		return err
+	}
+	if !refreshResponse.IsSuccess() {
+		return newAPIError(refreshResponse)
+	}
+	return nil
+}
+
+// WithLease runs fn while holding a lease on the machine, passing it the nonce to send with requests. The lease is
+// refreshed in the background while fn runs and released however fn returns, even if it panics.
+func (a *MachineAPI) WithLease(app string, id string, fn func(nonce string) error) (err error) {
+	lease, err := a.LockMachine(app, id, leaseTTL)
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(leaseTTL / 2 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				// A failed refresh is retried on the next tick, if the lease does expire the request made with it
+				// fails and reports that instead
+				_ = a.refreshLease(*lease, app, id, leaseTTL)
+			}
+		}
+	}()
+
+	defer func() {
+		close(stop)
+		wg.Wait()
+
+		releaseErr := a.ReleaseMachine(*lease, app, id)
+		// Destroying the machine also drops its lease
+		if err == nil && releaseErr != nil && !IsNotFound(releaseErr) {
+			err = releaseErr
+		}
+	}()
+
+	return fn(lease.Data.Nonce)
+}
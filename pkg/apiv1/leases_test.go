@@ -0,0 +1,192 @@
+package apiv1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	hreq "github.com/imroc/req/v3"
+)
+
+// leaseServer fakes the lease endpoints of a machine, answering releases with releaseStatus
+type leaseServer struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	releaseStatus int
+	released      []string
+}
+
+func newLeaseServer(t *testing.T, releaseStatus int) *leaseServer {
+	s := &leaseServer{releaseStatus: releaseStatus}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/v1/apps/app/machines/machine/lease"):
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"status": "success", "data": {"nonce": "nonce", "expires_at": 1700000000, "owner": "terraform"}}`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/v1/apps/app/machines/machine/lease":
+			s.mu.Lock()
+			s.released = append(s.released, r.Header.Get(NonceHeader))
+			s.mu.Unlock()
+			w.WriteHeader(s.releaseStatus)
+			if s.releaseStatus == http.StatusNotFound {
+				fmt.Fprint(w, `{"error": "machine not found"}`)
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *leaseServer) machineAPI() *MachineAPI {
+	return NewMachineAPI(hreq.C(), strings.TrimPrefix(s.URL, "http://"))
+}
+
+func (s *leaseServer) assertReleased(t *testing.T) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.released) != 1 || s.released[0] != "nonce" {
+		t.Errorf("lease releases = %v, want one release with the lease nonce", s.released)
+	}
+}
+
+func TestWithLease(t *testing.T) {
+	s := newLeaseServer(t, http.StatusOK)
+
+	var gotNonce string
+	err := s.machineAPI().WithLease("app", "machine", func(nonce string) error {
+		gotNonce = nonce
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithLease() = %v", err)
+	}
+	if gotNonce != "nonce" {
+		t.Errorf("fn got nonce %q, want %q", gotNonce, "nonce")
+	}
+	s.assertReleased(t)
+}
+
+func TestWithLeaseReleasesOnError(t *testing.T) {
+	s := newLeaseServer(t, http.StatusOK)
+
+	fnErr := errors.New("update failed")
+	err := s.machineAPI().WithLease("app", "machine", func(string) error {
+		return fnErr
+	})
+	if err != fnErr {
+		t.Errorf("WithLease() = %v, want %v", err, fnErr)
+	}
+	s.assertReleased(t)
+}
+
+func TestWithLeaseReleasesOnPanic(t *testing.T) {
+	s := newLeaseServer(t, http.StatusOK)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want the panic from fn", r)
+			}
+		}()
+		_ = s.machineAPI().WithLease("app", "machine", func(string) error {
+			panic("boom")
+		})
+	}()
+	s.assertReleased(t)
+}
+
+func TestWithLeaseReleaseErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		releaseStatus int
+		fnErr         error
+		wantErr       bool
+	}{
+		{name: "machine destroyed", releaseStatus: http.StatusNotFound},
+		{name: "release failed", releaseStatus: http.StatusBadRequest, wantErr: true},
+		{name: "fn error wins", releaseStatus: http.StatusBadRequest, fnErr: errors.New("update failed"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newLeaseServer(t, tt.releaseStatus)
+
+			err := s.machineAPI().WithLease("app", "machine", func(string) error {
+				return tt.fnErr
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithLease() = %v, want error: %v", err, tt.wantErr)
+			}
+			if tt.fnErr != nil && err != tt.fnErr {
+				t.Errorf("WithLease() = %v, want %v", err, tt.fnErr)
+			}
+			if tt.fnErr == nil && tt.wantErr {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.releaseStatus {
+					t.Errorf("WithLease() = %v, want the release error", err)
+				}
+			}
+			s.assertReleased(t)
+		})
+	}
+}
+
+func TestLeaseError(t *testing.T) {
+	apiErr := &APIError{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       error
+	}{
+		{
+			name:       "conflict",
+			statusCode: http.StatusConflict,
+			body:       `{"status": "failure", "data": {"owner": "someone@example.com", "expires_at": 1700000000}}`,
+			want:       &LeaseHeldError{App: "app", ID: "machine", Owner: "someone@example.com", ExpiresAt: 1700000000},
+		},
+		{
+			name:       "conflict without body",
+			statusCode: http.StatusConflict,
+			want:       &LeaseHeldError{App: "app", ID: "machine"},
+		},
+		{
+			name:       "owner in body",
+			statusCode: http.StatusBadRequest,
+			body:       `{"data": {"owner": "someone@example.com"}}`,
+			want:       &LeaseHeldError{App: "app", ID: "machine", Owner: "someone@example.com"},
+		},
+		{
+			name:       "other error",
+			statusCode: http.StatusBadRequest,
+			body:       `{"error": "bad ttl"}`,
+			want:       apiErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := leaseError("app", "machine", tt.statusCode, []byte(tt.body), apiErr)
+			if want, ok := tt.want.(*LeaseHeldError); ok {
+				held, ok := got.(*LeaseHeldError)
+				if !ok || *held != *want {
+					t.Errorf("leaseError() = %#v, want %#v", got, want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("leaseError() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
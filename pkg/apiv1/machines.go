@@ -6,6 +6,9 @@ import (
 	"github.com/Khan/genqlient/graphql"
 	hreq "github.com/imroc/req/v3"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -24,15 +27,103 @@ type MachineMount struct {
 	Volume    string `json:"volume"`
 }
 
+type TLSOptions struct {
+	Alpn     []string `json:"alpn,omitempty"`
+	Versions []string `json:"versions,omitempty"`
+}
+
+type HTTPResponseOptions struct {
+	Headers map[string]interface{} `json:"headers,omitempty"`
+}
+
+type HTTPOptions struct {
+	Compress *bool                `json:"compress,omitempty"`
+	Response *HTTPResponseOptions `json:"response,omitempty"`
+}
+
+type ProxyProtoOptions struct {
+	Version string `json:"version,omitempty"`
+}
+
 type Port struct {
-	Port     int64    `json:"port"`
-	Handlers []string `json:"handlers"`
+	Port              int64              `json:"port"`
+	Handlers          []string           `json:"handlers"`
+	ForceHttps        *bool              `json:"force_https,omitempty"`
+	TLSOptions        *TLSOptions        `json:"tls_options,omitempty"`
+	HTTPOptions       *HTTPOptions       `json:"http_options,omitempty"`
+	ProxyProtoOptions *ProxyProtoOptions `json:"proxy_proto_options,omitempty"`
+}
+
+type ServiceConcurrency struct {
+	Type      string `json:"type"`
+	HardLimit int64  `json:"hard_limit,omitempty"`
+	SoftLimit int64  `json:"soft_limit,omitempty"`
 }
 
 type Service struct {
-	Ports        []Port `json:"ports"`
-	Protocol     string `json:"protocol"`
-	InternalPort int64  `json:"internal_port"`
+	Ports        []Port              `json:"ports"`
+	Protocol     string              `json:"protocol"`
+	InternalPort int64               `json:"internal_port"`
+	Autostop     *bool               `json:"autostop,omitempty"`
+	Autostart    *bool               `json:"autostart,omitempty"`
+	Concurrency  *ServiceConcurrency `json:"concurrency,omitempty"`
+}
+
+type MachineHTTPHeader struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type MachineCheck struct {
+	Type        string              `json:"type"`
+	Port        int64               `json:"port"`
+	Interval    string              `json:"interval,omitempty"`
+	Timeout     string              `json:"timeout,omitempty"`
+	GracePeriod string              `json:"grace_period,omitempty"`
+	Method      string              `json:"method,omitempty"`
+	Path        string              `json:"path,omitempty"`
+	Protocol    string              `json:"protocol,omitempty"`
+	Headers     []MachineHTTPHeader `json:"headers,omitempty"`
+}
+
+type CheckStatus struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Output    string    `json:"output"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type MachineEvent struct {
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Source    string `json:"source"`
+	Timestamp int64  `json:"timestamp"`
+	Request   struct {
+		ExitEvent struct {
+			ExitCode  int  `json:"exit_code"`
+			OOMKilled bool `json:"oom_killed"`
+		} `json:"exit_event"`
+	} `json:"request"`
+}
+
+type MachineRestart struct {
+	Policy     string `json:"policy,omitempty"`
+	MaxRetries int64  `json:"max_retries,omitempty"`
+}
+
+type MachineFile struct {
+	GuestPath  string `json:"guest_path"`
+	RawValue   string `json:"raw_value,omitempty"`
+	SecretName string `json:"secret_name,omitempty"`
+}
+
+type MachineProcess struct {
+	Name       string            `json:"name,omitempty"`
+	Entrypoint []string          `json:"entrypoint,omitempty"`
+	Cmd        []string          `json:"cmd,omitempty"`
+	Exec       []string          `json:"exec,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+	User       string            `json:"user,omitempty"`
 }
 
 type InitConfig struct {
@@ -42,12 +133,20 @@ type InitConfig struct {
 }
 
 type MachineConfig struct {
-	Image    string            `json:"image"`
-	Env      map[string]string `json:"env"`
-	Init     InitConfig        `json:"init,omitempty"`
-	Mounts   []MachineMount    `json:"mounts,omitempty"`
-	Services []Service         `json:"services"`
-	Guest    GuestConfig       `json:"guest,omitempty"`
+	Image       string                  `json:"image"`
+	Env         map[string]string       `json:"env"`
+	Metadata    map[string]string       `json:"metadata,omitempty"`
+	Init        InitConfig              `json:"init,omitempty"`
+	Mounts      []MachineMount          `json:"mounts,omitempty"`
+	Files       []MachineFile           `json:"files,omitempty"`
+	Processes   []MachineProcess        `json:"processes,omitempty"`
+	Services    []Service               `json:"services"`
+	Checks      map[string]MachineCheck `json:"checks,omitempty"`
+	Guest       GuestConfig             `json:"guest,omitempty"`
+	Restart     *MachineRestart         `json:"restart,omitempty"`
+	AutoDestroy bool                    `json:"auto_destroy,omitempty"`
+	Schedule    string                  `json:"schedule,omitempty"`
+	Standbys    []string                `json:"standbys,omitempty"`
 }
 
 type GuestConfig struct {
@@ -62,6 +161,14 @@ type MachineCreateOrUpdateRequest struct {
 	Config MachineConfig `json:"config"`
 }
 
+type ImageRef struct {
+	Registry   string            `json:"registry"`
+	Repository string            `json:"repository"`
+	Tag        string            `json:"tag"`
+	Digest     string            `json:"digest"`
+	Labels     map[string]string `json:"labels"`
+}
+
 type MachineResponse struct {
 	ID         string `json:"id"`
 	Name       string `json:"name"`
@@ -77,28 +184,28 @@ type MachineResponse struct {
 			Cmd        []string `json:"cmd"`
 			//Tty        bool        `json:"tty"`
 		} `json:"init"`
-		Image    string      `json:"image"`
-		Metadata interface{} `json:"metadata"`
-		Restart  struct {
-			Policy string `json:"policy"`
-		} `json:"restart"`
-		Services []Service      `json:"services"`
-		Mounts   []MachineMount `json:"mounts"`
-		Guest    struct {
+		Image       string                  `json:"image"`
+		Metadata    map[string]string       `json:"metadata"`
+		Restart     MachineRestart          `json:"restart"`
+		AutoDestroy bool                    `json:"auto_destroy"`
+		Schedule    string                  `json:"schedule"`
+		Standbys    []string                `json:"standbys"`
+		Services    []Service               `json:"services"`
+		Checks      map[string]MachineCheck `json:"checks"`
+		Mounts      []MachineMount          `json:"mounts"`
+		Files       []MachineFile           `json:"files"`
+		Processes   []MachineProcess        `json:"processes"`
+		Guest       struct {
 			CPUKind  string `json:"cpu_kind"`
 			Cpus     int    `json:"cpus"`
 			MemoryMb int    `json:"memory_mb"`
 		} `json:"guest"`
 	} `json:"config"`
-	ImageRef struct {
-		Registry   string `json:"registry"`
-		Repository string `json:"repository"`
-		Tag        string `json:"tag"`
-		Digest     string `json:"digest"`
-		Labels     struct {
-		} `json:"labels"`
-	} `json:"image_ref"`
-	CreatedAt time.Time `json:"created_at"`
+	ImageRef  ImageRef       `json:"image_ref"`
+	Checks    []CheckStatus  `json:"checks"`
+	Events    []MachineEvent `json:"events"`
+	Cordoned  *bool          `json:"cordoned,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
 }
 
 type MachineLease struct {
@@ -117,26 +224,124 @@ func NewMachineAPI(httpClient *hreq.Client, endpoint string) *MachineAPI {
 	}
 }
 
+// LockMachine takes a lease on the machine for timeout seconds. Prefer WithLease, which also refreshes and releases it.
 func (a *MachineAPI) LockMachine(app string, id string, timeout int) (*MachineLease, error) {
 	var res MachineLease
-	_, err := a.httpClient.R().SetResult(&res).Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/lease/?ttl=%d", a.endpoint, app, id, timeout))
+	leaseResponse, err := a.request().SetResult(&res).Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/lease/?ttl=%d", a.endpoint, app, id, timeout))
 	if err != nil {
 		return nil, err
 	}
+	if !leaseResponse.IsSuccess() {
+		return nil, leaseError(app, id, leaseResponse.StatusCode, leaseResponse.Bytes(), newAPIError(leaseResponse))
+	}
+	if res.Status != "success" {
+		return nil, &LeaseHeldError{App: app, ID: id, Owner: res.Data.Owner, ExpiresAt: res.Data.ExpiresAt}
+	}
 	return &res, nil
 }
 
 func (a *MachineAPI) ReleaseMachine(lease MachineLease, app string, id string) error {
-	_, err := a.httpClient.R().SetHeader(NonceHeader, lease.Data.Nonce).Delete(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/lease", a.endpoint, app, id))
+	releaseResponse, err := a.request().SetHeader(NonceHeader, lease.Data.Nonce).Delete(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/lease", a.endpoint, app, id))
 	if err != nil {
 		return err
 	}
+	if !releaseResponse.IsSuccess() {
+		return newAPIError(releaseResponse)
+	}
 	return nil
 }
 
-func (a *MachineAPI) WaitForMachine(app string, id string, instanceID string) error {
-	_, err := a.httpClient.R().Get(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/wait?instance_id=%s", a.endpoint, app, id, instanceID))
-	return err
+// The wait endpoint blocks for at most a minute, longer waits are split over several requests
+const maxWaitRequestSeconds = 60
+
+// WaitForMachine blocks until the machine reaches state (`started`, `stopped` or `destroyed`) or the timeout runs out.
+// When the machine doesn't get there in time the error describes the state it was left in and its latest events.
+func (a *MachineAPI) WaitForMachine(app string, id string, instanceID string, state string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		remaining := int(time.Until(deadline).Seconds())
+		if remaining <= 0 {
+			return a.waitFailure(app, id, state, timeout)
+		}
+		if remaining > maxWaitRequestSeconds {
+			remaining = maxWaitRequestSeconds
+		}
+
+		query := url.Values{}
+		query.Set("state", state)
+		query.Set("timeout", strconv.Itoa(remaining))
+		if instanceID != "" {
+			query.Set("instance_id", instanceID)
+		}
+
+		waitResponse, err := a.request().Get(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/wait?%s", a.endpoint, app, id, query.Encode()))
+		if err != nil {
+			return err
+		}
+
+		switch waitResponse.StatusCode {
+		case http.StatusOK:
+			return nil
+		case http.StatusRequestTimeout:
+			continue
+		default:
+			return newAPIError(waitResponse)
+		}
+	}
+}
+
+func (a *MachineAPI) waitFailure(app string, id string, state string, timeout time.Duration) error {
+	var machine MachineResponse
+	err := a.ReadMachine(app, id, &machine)
+	if err != nil {
+		return errors.New(fmt.Sprintf("machine %s did not reach state %s within %s, reading it failed: %s", id, state, timeout, err))
+	}
+
+	var events []string
+	for i, event := range machine.Events {
+		// Events are returned newest first, the last few are enough to tell a crash loop from a slow start
+		if i == 5 {
+			break
+		}
+		description := fmt.Sprintf("%s/%s", event.Type, event.Status)
+		if event.Type == "exit" {
+			description += fmt.Sprintf(" (exit code %d", event.Request.ExitEvent.ExitCode)
+			if event.Request.ExitEvent.OOMKilled {
+				description += ", out of memory"
+			}
+			description += ")"
+		}
+		events = append(events, description)
+	}
+
+	return errors.New(fmt.Sprintf("machine %s did not reach state %s within %s, it is %s. Latest events: %s", id, state, timeout, machine.State, strings.Join(events, ", ")))
+}
+
+// WaitForHealthChecks polls the machine until every one of its checks reports as passing or the timeout is reached
+func (a *MachineAPI) WaitForHealthChecks(app string, id string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		var machine MachineResponse
+		err := a.ReadMachine(app, id, &machine)
+		if err != nil {
+			return err
+		}
+
+		var failing []string
+		for _, check := range machine.Checks {
+			if check.Status != "passing" {
+				failing = append(failing, fmt.Sprintf("%s (%s): %s", check.Name, check.Status, check.Output))
+			}
+		}
+		if len(machine.Checks) >= len(machine.Config.Checks) && len(failing) == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return errors.New(fmt.Sprintf("health checks did not pass within %s: %v", timeout, failing))
+		}
+		time.Sleep(2 * time.Second)
+	}
 }
 
 // CreateMachine takes a MachineCreateOrUpdateRequest and creates the requested machine in the given app and then writes the response into the `res` param
@@ -150,14 +355,15 @@ func (a *MachineAPI) CreateMachine(req MachineCreateOrUpdateRequest, app string,
 	if req.Config.Guest.MemoryMb == 0 {
 		req.Config.Guest.MemoryMb = 256
 	}
-	createResponse, err := a.httpClient.R().SetBody(req).SetResult(res).Post(fmt.Sprintf("http://%s/v1/apps/%s/machines", a.endpoint, app))
+	// A create that failed with a server error might still have created the machine, so only rate limits are retried
+	createResponse, err := a.request().SetRetryCondition(isRateLimited).SetBody(req).SetResult(res).Post(fmt.Sprintf("http://%s/v1/apps/%s/machines", a.endpoint, app))
 
 	if err != nil {
 		return err
 	}
 
 	if createResponse.StatusCode != http.StatusCreated && createResponse.StatusCode != http.StatusOK {
-		return errors.New(fmt.Sprintf("Create request failed: %s, %+v", createResponse.Status, createResponse))
+		return newAPIError(createResponse)
 	}
 	return nil
 }
@@ -174,58 +380,241 @@ func (a *MachineAPI) UpdateMachine(req MachineCreateOrUpdateRequest, app string,
 		//You can't have a machine with no memory
 		req.Config.Guest.MemoryMb = 256
 	}
-	lease, err := a.LockMachine(app, id, 30)
+	return a.WithLease(app, id, func(nonce string) error {
+		reqRes, err := a.request().SetBody(req).SetResult(res).SetHeader(NonceHeader, nonce).Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s", a.endpoint, app, id))
+		if err != nil {
+			return err
+		}
+		if reqRes.StatusCode != http.StatusCreated && reqRes.StatusCode != http.StatusOK {
+			return newAPIError(reqRes)
+		}
+		return nil
+	})
+}
+
+// StartMachine asks the orchestrator to start a stopped machine
+func (a *MachineAPI) StartMachine(app string, id string) error {
+	startResponse, err := a.request().Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/start", a.endpoint, app, id))
+	if err != nil {
+		return err
+	}
+	if startResponse.StatusCode != http.StatusOK {
+		return newAPIError(startResponse)
+	}
+	return nil
+}
+
+type MachineStopRequest struct {
+	Signal  string `json:"signal,omitempty"`
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// StopMachine asks the orchestrator to stop a running machine
+func (a *MachineAPI) StopMachine(app string, id string, req MachineStopRequest) error {
+	return a.stopMachine(app, id, "", req)
+}
+
+func (a *MachineAPI) stopMachine(app string, id string, nonce string, req MachineStopRequest) error {
+	r := a.request().SetBody(req)
+	if nonce != "" {
+		r.SetHeader(NonceHeader, nonce)
+	}
+	stopResponse, err := r.Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/stop", a.endpoint, app, id))
 	if err != nil {
 		return err
 	}
-	reqRes, err := a.httpClient.R().SetBody(req).SetResult(res).SetHeader(NonceHeader, lease.Data.Nonce).Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s", a.endpoint, app, id))
+	if stopResponse.StatusCode != http.StatusOK {
+		return newAPIError(stopResponse)
+	}
+	return nil
+}
+
+// SuspendMachine stops the machine after snapshotting its memory so that the next start resumes where it left off
+func (a *MachineAPI) SuspendMachine(app string, id string) error {
+	suspendResponse, err := a.request().Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/suspend", a.endpoint, app, id))
 	if err != nil {
 		return err
 	}
-	err = a.ReleaseMachine(*lease, app, id)
+	if suspendResponse.StatusCode != http.StatusOK {
+		return newAPIError(suspendResponse)
+	}
+	return nil
+}
+
+// CordonMachine takes the machine out of fly-proxy load balancing without stopping it
+func (a *MachineAPI) CordonMachine(app string, id string) error {
+	cordonResponse, err := a.request().Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/cordon", a.endpoint, app, id))
+	if err != nil {
+		return err
+	}
+	if cordonResponse.StatusCode != http.StatusOK {
+		return newAPIError(cordonResponse)
+	}
+	return nil
+}
+
+// UncordonMachine puts a cordoned machine back into fly-proxy load balancing
+func (a *MachineAPI) UncordonMachine(app string, id string) error {
+	uncordonResponse, err := a.request().Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/uncordon", a.endpoint, app, id))
 	if err != nil {
 		return err
 	}
-	if reqRes.StatusCode != http.StatusCreated && reqRes.StatusCode != http.StatusOK {
-		return errors.New(fmt.Sprintf("Update request failed: %s, %+v", reqRes.Status, reqRes))
+	if uncordonResponse.StatusCode != http.StatusOK {
+		return newAPIError(uncordonResponse)
 	}
 	return nil
 }
 
-func (a *MachineAPI) ReadMachine(app string, id string, res *MachineResponse) (*hreq.Response, error) {
-	return a.httpClient.R().SetResult(res).Get(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s", a.endpoint, app, id))
+type MachineExecRequest struct {
+	Command []string `json:"command"`
+	// Timeout in seconds
+	Timeout int `json:"timeout,omitempty"`
 }
 
-func (a *MachineAPI) DeleteMachine(app string, id string, maxRetries int) error {
-	deleted := false
-	for i := 0; i < maxRetries; i++ {
-		var machine MachineResponse
-		readResponse, err := a.httpClient.R().SetResult(&machine).Get(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s", a.endpoint, app, id))
+type MachineExecResponse struct {
+	StdOut   string `json:"stdout"`
+	StdErr   string `json:"stderr"`
+	ExitCode int64  `json:"exit_code"`
+}
+
+// execTimeoutSlack is added to the command timeout for the request to the api, so the command times out first
+const execTimeoutSlack = 30 * time.Second
+
+// ExecMachine runs a command in the machine and returns its output once it exits. A command exiting with a non zero
+// code is not an error.
+func (a *MachineAPI) ExecMachine(app string, id string, req MachineExecRequest) (*MachineExecResponse, error) {
+	var res MachineExecResponse
+	// The command can run for longer than the client's own timeout allows
+	client := a.httpClient.Clone().SetTimeout(time.Duration(req.Timeout)*time.Second + execTimeoutSlack)
+	// Commands like migrations aren't safe to run twice, so only rate limits are retried
+	execResponse, err := client.R().
+		SetRetryCount(retryCount).
+		SetRetryBackoffInterval(retryMinBackoff, retryMaxBackoff).
+		SetRetryCondition(isRateLimited).
+		SetBody(req).SetResult(&res).Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/exec", a.endpoint, app, id))
+	if err != nil {
+		return nil, err
+	}
+	if execResponse.StatusCode != http.StatusOK {
+		return nil, newAPIError(execResponse)
+	}
+	return &res, nil
+}
+
+// MachineListFilters narrows down ListMachines, empty fields don't filter
+type MachineListFilters struct {
+	Region string
+	// State is a comma separated list of states, like `started,stopped`
+	State    string
+	Metadata map[string]string
+}
+
+// ListMachines returns the machines in the app that match all filters
+func (a *MachineAPI) ListMachines(app string, filters MachineListFilters) ([]MachineResponse, error) {
+	query := url.Values{}
+	if filters.Region != "" {
+		query.Set("region", filters.Region)
+	}
+	if filters.State != "" {
+		query.Set("state", filters.State)
+	}
+	for k, v := range filters.Metadata {
+		query.Set("metadata."+k, v)
+	}
+
+	var machines []MachineResponse
+	listResponse, err := a.request().SetResult(&machines).Get(fmt.Sprintf("http://%s/v1/apps/%s/machines?%s", a.endpoint, app, query.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	if listResponse.StatusCode != http.StatusOK {
+		return nil, newAPIError(listResponse)
+	}
+	return machines, nil
+}
+
+// ReadMachine writes the machine into `res`. Use IsNotFound on the error to tell a machine that is gone apart from a
+// failed request.
+func (a *MachineAPI) ReadMachine(app string, id string, res *MachineResponse) error {
+	readResponse, err := a.request().SetResult(res).Get(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s", a.endpoint, app, id))
+	if err != nil {
+		return err
+	}
+	if readResponse.StatusCode != http.StatusOK {
+		return newAPIError(readResponse)
+	}
+	return nil
+}
+
+// MachineDestroyOptions configures how DeleteMachine tears a machine down
+type MachineDestroyOptions struct {
+	// Signal and StopTimeout are sent with the stop request, empty values use the machine's defaults
+	Signal      string
+	StopTimeout time.Duration
+	// Force destroys the machine without stopping it first
+	Force bool
+	// Timeout is how long to wait for the machine to be destroyed
+	Timeout time.Duration
+}
+
+// DeleteMachine stops the machine, destroys it and waits for it to be destroyed, holding its lease while doing so. A
+// machine that is already gone counts as deleted.
+func (a *MachineAPI) DeleteMachine(app string, id string, opts MachineDestroyOptions) error {
+	deadline := time.Now().Add(opts.Timeout)
+
+	err := a.WithLease(app, id, func(nonce string) error {
+		if !opts.Force {
+			err := a.stopForDestroy(app, id, nonce, opts, deadline)
+			if err != nil {
+				return err
+			}
+		}
+		return a.destroyMachine(app, id, nonce, opts.Force)
+	})
+	if err == nil {
+		err = a.WaitForMachine(app, id, "", "destroyed", time.Until(deadline))
+	}
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// stopForDestroy brings the machine to a state it can be destroyed from
+func (a *MachineAPI) stopForDestroy(app string, id string, nonce string, opts MachineDestroyOptions, deadline time.Time) error {
+	var machine MachineResponse
+	err := a.ReadMachine(app, id, &machine)
+	if err != nil {
+		return err
+	}
+
+	switch machine.State {
+	case "created", "starting", "started", "replacing":
+		req := MachineStopRequest{Signal: opts.Signal}
+		if opts.StopTimeout != 0 {
+			req.Timeout = opts.StopTimeout.String()
+		}
+		err = a.stopMachine(app, id, nonce, req)
 		if err != nil {
 			return err
 		}
+		return a.WaitForMachine(app, id, machine.InstanceID, "stopped", time.Until(deadline))
+	case "stopping":
+		return a.WaitForMachine(app, id, machine.InstanceID, "stopped", time.Until(deadline))
+	case "suspending":
+		return a.WaitForMachine(app, id, machine.InstanceID, "suspended", time.Until(deadline))
+	default:
+		return nil
+	}
+}
 
-		if readResponse.StatusCode == 200 {
-			if machine.State == "started" || machine.State == "starting" || machine.State == "replacing" {
-				_, _ = a.httpClient.R().Post(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s/stop", a.endpoint, app, id))
-			}
-			if machine.State == "stopping" || machine.State == "destroying" {
-				time.Sleep(5 * time.Second)
-			}
-			if machine.State == "stopped" || machine.State == "replaced" {
-				_, err = a.httpClient.R().Delete(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s", a.endpoint, app, id))
-				if err != nil {
-					return err
-				}
-			}
-			if machine.State == "destroyed" {
-				deleted = true
-				break
-			}
-		}
+func (a *MachineAPI) destroyMachine(app string, id string, nonce string, force bool) error {
+	deleteResponse, err := a.request().SetHeader(NonceHeader, nonce).Delete(fmt.Sprintf("http://%s/v1/apps/%s/machines/%s?force=%t", a.endpoint, app, id, force))
+	if err != nil {
+		return err
 	}
-	if !deleted {
-		return errors.New("max retries exceeded")
+	if !deleteResponse.IsSuccess() {
+		return newAPIError(deleteResponse)
 	}
 	return nil
 }
@@ -0,0 +1,69 @@
+package apiv1
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// CreateVolumeRequest creates a volume through the machines api, which unlike graphql can restore a snapshot and
+// configure snapshot retention
+type CreateVolumeRequest struct {
+	Name              string `json:"name"`
+	Region            string `json:"region"`
+	SizeGb            int    `json:"size_gb"`
+	Encrypted         *bool  `json:"encrypted,omitempty"`
+	RequireUniqueZone *bool  `json:"require_unique_zone,omitempty"`
+	SnapshotID        string `json:"snapshot_id,omitempty"`
+	SnapshotRetention *int   `json:"snapshot_retention,omitempty"`
+}
+
+type UpdateVolumeRequest struct {
+	SnapshotRetention *int `json:"snapshot_retention,omitempty"`
+}
+
+type VolumeResponse struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	State             string `json:"state"`
+	Region            string `json:"region"`
+	SizeGb            int    `json:"size_gb"`
+	Encrypted         bool   `json:"encrypted"`
+	SnapshotRetention int    `json:"snapshot_retention"`
+}
+
+func (a *MachineAPI) CreateVolume(app string, req CreateVolumeRequest) (*VolumeResponse, error) {
+	var res VolumeResponse
+	// A create that failed with a server error might still have created the volume, so only rate limits are retried
+	createResponse, err := a.request().SetRetryCondition(isRateLimited).SetBody(req).SetResult(&res).Post(fmt.Sprintf("http://%s/v1/apps/%s/volumes", a.endpoint, app))
+	if err != nil {
+		return nil, err
+	}
+	if createResponse.StatusCode != http.StatusCreated && createResponse.StatusCode != http.StatusOK {
+		return nil, newAPIError(createResponse)
+	}
+	return &res, nil
+}
+
+func (a *MachineAPI) UpdateVolume(app string, id string, req UpdateVolumeRequest) (*VolumeResponse, error) {
+	var res VolumeResponse
+	updateResponse, err := a.request().SetBody(req).SetResult(&res).Put(fmt.Sprintf("http://%s/v1/apps/%s/volumes/%s", a.endpoint, app, id))
+	if err != nil {
+		return nil, err
+	}
+	if updateResponse.StatusCode != http.StatusOK {
+		return nil, newAPIError(updateResponse)
+	}
+	return &res, nil
+}
+
+func (a *MachineAPI) ReadVolume(app string, id string) (*VolumeResponse, error) {
+	var res VolumeResponse
+	readResponse, err := a.request().SetResult(&res).Get(fmt.Sprintf("http://%s/v1/apps/%s/volumes/%s", a.endpoint, app, id))
+	if err != nil {
+		return nil, err
+	}
+	if readResponse.StatusCode != http.StatusOK {
+		return nil, newAPIError(readResponse)
+	}
+	return &res, nil
+}
@@ -0,0 +1,198 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	flyRegistry       = "registry.fly.io"
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference splits an image reference like `nginx`, `nginx:1.23` or `registry.fly.io/app:deployment-1` into
+// its parts, filling in the same defaults as docker does
+func ParseReference(image string) Reference {
+	var ref Reference
+
+	remainder := image
+	if i := strings.Index(remainder, "@"); i != -1 {
+		ref.Digest = remainder[i+1:]
+		remainder = remainder[:i]
+	}
+
+	parts := strings.SplitN(remainder, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry = parts[0]
+		remainder = parts[1]
+		if ref.Registry == "docker.io" || ref.Registry == "index.docker.io" {
+			ref.Registry = dockerHubRegistry
+		}
+	} else {
+		ref.Registry = dockerHubRegistry
+	}
+
+	if i := strings.LastIndex(remainder, ":"); i != -1 {
+		ref.Tag = remainder[i+1:]
+		remainder = remainder[:i]
+	}
+	if ref.Tag == "" {
+		ref.Tag = "latest"
+	}
+
+	if ref.Registry == dockerHubRegistry && !strings.Contains(remainder, "/") {
+		remainder = "library/" + remainder
+	}
+	ref.Repository = remainder
+
+	return ref
+}
+
+// Resolver looks up which digest a tag currently points to
+type Resolver struct {
+	httpClient *http.Client
+	flyToken   string
+}
+
+func NewResolver(flyToken string) *Resolver {
+	return &Resolver{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		flyToken:   flyToken,
+	}
+}
+
+// ResolveDigest asks the registry which manifest digest the image's tag points to. Images that already contain a
+// digest are returned as is.
+func (r *Resolver) ResolveDigest(ctx context.Context, image string) (string, error) {
+	ref := ParseReference(image)
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.Registry, ref.Repository, ref.Tag)
+
+	res, err := r.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		token, err := r.token(ctx, ref, res.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", err
+		}
+		res, err = r.headManifest(ctx, manifestURL, token)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", errors.New(fmt.Sprintf("Manifest request for %s failed: %s", image, res.Status))
+	}
+
+	digest := res.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", errors.New(fmt.Sprintf("Registry %s did not return a digest for %s", ref.Registry, image))
+	}
+	return digest, nil
+}
+
+func (r *Resolver) headManifest(ctx context.Context, manifestURL string, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	return res, nil
+}
+
+// token follows the bearer challenge from the registry to get a pull token. The fly registry needs the fly api token
+// as basic auth, every other registry is accessed anonymously.
+func (r *Resolver) token(ctx context.Context, ref Reference, challenge string) (string, error) {
+	params := parseChallenge(challenge)
+	realm, ok := params["realm"]
+	if !ok {
+		return "", errors.New(fmt.Sprintf("Registry %s returned an unsupported auth challenge: %q", ref.Registry, challenge))
+	}
+
+	query := url.Values{}
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	if scope, ok := params["scope"]; ok {
+		query.Set("scope", scope)
+	} else {
+		query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if ref.Registry == flyRegistry {
+		req.SetBasicAuth("x", r.flyToken)
+	}
+
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", errors.New(fmt.Sprintf("Token request to %s failed: %s", realm, res.Status))
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	if err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// parseChallenge reads the key="value" pairs out of a `Bearer realm="...",service="..."` header
+func parseChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+	for _, match := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	return params
+}
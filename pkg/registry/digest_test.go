@@ -0,0 +1,146 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Reference
+	}{
+		{
+			image: "nginx",
+			want:  Reference{Registry: dockerHubRegistry, Repository: "library/nginx", Tag: "latest"},
+		},
+		{
+			image: "nginx:1.23",
+			want:  Reference{Registry: dockerHubRegistry, Repository: "library/nginx", Tag: "1.23"},
+		},
+		{
+			image: "grafana/grafana:9.3.2",
+			want:  Reference{Registry: dockerHubRegistry, Repository: "grafana/grafana", Tag: "9.3.2"},
+		},
+		{
+			image: "docker.io/nginx",
+			want:  Reference{Registry: dockerHubRegistry, Repository: "library/nginx", Tag: "latest"},
+		},
+		{
+			image: "index.docker.io/library/nginx:1.23",
+			want:  Reference{Registry: dockerHubRegistry, Repository: "library/nginx", Tag: "1.23"},
+		},
+		{
+			image: "registry.fly.io/app:deployment-1",
+			want:  Reference{Registry: flyRegistry, Repository: "app", Tag: "deployment-1"},
+		},
+		{
+			image: "ghcr.io/org/team/image",
+			want:  Reference{Registry: "ghcr.io", Repository: "org/team/image", Tag: "latest"},
+		},
+		{
+			image: "localhost:5000/image",
+			want:  Reference{Registry: "localhost:5000", Repository: "image", Tag: "latest"},
+		},
+		{
+			image: "localhost:5000/image:v2",
+			want:  Reference{Registry: "localhost:5000", Repository: "image", Tag: "v2"},
+		},
+		{
+			image: "localhost/image:v2",
+			want:  Reference{Registry: "localhost", Repository: "image", Tag: "v2"},
+		},
+		{
+			image: "nginx@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+			want: Reference{
+				Registry:   dockerHubRegistry,
+				Repository: "library/nginx",
+				Tag:        "latest",
+				Digest:     "sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+			},
+		},
+		{
+			image: "registry.fly.io:443/app:v1@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+			want: Reference{
+				Registry:   "registry.fly.io:443",
+				Repository: "app",
+				Tag:        "v1",
+				Digest:     "sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got := ParseReference(tt.image)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseReference(%q) = %+v, want %+v", tt.image, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge string
+		want      map[string]string
+	}{
+		{
+			name:      "docker hub",
+			challenge: `Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`,
+			want: map[string]string{
+				"realm":   "https://auth.docker.io/token",
+				"service": "registry.docker.io",
+				"scope":   "repository:library/nginx:pull",
+			},
+		},
+		{
+			name:      "spaces between params",
+			challenge: `Bearer realm="https://ghcr.io/token", service="ghcr.io", scope="repository:org/image:pull"`,
+			want: map[string]string{
+				"realm":   "https://ghcr.io/token",
+				"service": "ghcr.io",
+				"scope":   "repository:org/image:pull",
+			},
+		},
+		{
+			name:      "param names are case insensitive",
+			challenge: `Bearer Realm="https://auth.example.com/token",SERVICE="example"`,
+			want: map[string]string{
+				"realm":   "https://auth.example.com/token",
+				"service": "example",
+			},
+		},
+		{
+			name:      "commas inside quoted values",
+			challenge: `Bearer realm="https://auth.example.com/token",scope="repository:a:pull,push"`,
+			want: map[string]string{
+				"realm": "https://auth.example.com/token",
+				"scope": "repository:a:pull,push",
+			},
+		},
+		{
+			name:      "empty value",
+			challenge: `Bearer realm="https://auth.example.com/token",service=""`,
+			want: map[string]string{
+				"realm":   "https://auth.example.com/token",
+				"service": "",
+			},
+		},
+		{
+			name:      "no params",
+			challenge: `Basic`,
+			want:      map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseChallenge(tt.challenge)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseChallenge(%q) = %v, want %v", tt.challenge, got, tt.want)
+			}
+		})
+	}
+}